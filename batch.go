@@ -0,0 +1,310 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultMaxConcurrency is the number of concurrent requests the batch helpers issue unless configured otherwise.
+const defaultMaxConcurrency = 4
+
+// WithMaxConcurrency returns an Option that limits how many requests the batch and multi-target helpers
+// issue concurrently. Values below 1 are treated as 1. The default is 4.
+func WithMaxConcurrency(n int) Option {
+	return func(c *Client) {
+		if n < 1 {
+			n = 1
+		}
+		c.maxConcurrency = n
+	}
+}
+
+// concurrencyLimit returns the configured maximum concurrency, falling back to the default.
+func (c *Client) concurrencyLimit() int {
+	if c.maxConcurrency < 1 {
+		return defaultMaxConcurrency
+	}
+	return c.maxConcurrency
+}
+
+// runConcurrently calls fn for every index in [0, n) with at most the client's maximum concurrency.
+// The first error cancels the context passed to the other calls, no further calls are started, and
+// that error is returned once all started calls have finished. If ctx is cancelled before all calls
+// were started, the context's error is returned.
+func (c *Client) runConcurrently(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, c.concurrencyLimit())
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return parent.Err()
+}
+
+// TranslateUnique translates texts that may contain duplicates, sending every distinct text only once to
+// reduce billed characters. It returns a map from each original text to its translation; use
+// TranslationsInOrder to rebuild a slice matching the order of texts. The distinct texts are sent in
+// batches as by TranslateTexts.
+func (c *Client) TranslateUnique(ctx context.Context, texts []string, targetLang string) (map[string]*Translation, error) {
+	seen := make(map[string]bool, len(texts))
+	var unique []string
+	for _, text := range texts {
+		if !seen[text] {
+			seen[text] = true
+			unique = append(unique, text)
+		}
+	}
+	if len(unique) == 0 {
+		return map[string]*Translation{}, nil
+	}
+
+	translations, err := c.translateTexts(ctx, unique, TranslateTextOptions{TargetLang: targetLang})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*Translation, len(unique))
+	for i, text := range unique {
+		result[text] = translations[i]
+	}
+	return result, nil
+}
+
+// TranslationsInOrder returns the translations from a map returned by TranslateUnique in the order of texts,
+// repeating the translation for duplicate texts. Texts missing from the map yield nil entries.
+func TranslationsInOrder(texts []string, translations map[string]*Translation) []*Translation {
+	ordered := make([]*Translation, len(texts))
+	for i, text := range texts {
+		ordered[i] = translations[text]
+	}
+	return ordered
+}
+
+// TranslateToTargets translates the texts in opts into every language of targetLangs and returns the
+// translations keyed by target language. opts.TargetLang is ignored.
+//
+// If opts.SourceLang is empty, the texts are first translated into targetLangs[0] alone, and the source
+// language DeepL detected there is sent as an explicit SourceLang for the remaining targets. This avoids
+// detecting the source once per target and keeps all targets consistent, at the cost of waiting for the
+// first translation before the others start. All texts are therefore assumed to share one source language.
+// The texts are sent in batches as by TranslateTexts, and the remaining targets are translated concurrently,
+// bounded by WithMaxConcurrency.
+// Once DeepL reports that the character limit is reached, no further targets are requested, and the
+// translations finished so far are returned along with the error, for which IsQuotaExceeded reports true.
+func (c *Client) TranslateToTargets(ctx context.Context, opts TranslateTextOptions, targetLangs []string) (map[string][]*Translation, error) {
+	result := make(map[string][]*Translation, len(targetLangs))
+	if len(targetLangs) == 0 {
+		return result, nil
+	}
+
+	remaining := targetLangs
+	if opts.SourceLang == "" {
+		first := opts
+		first.TargetLang = targetLangs[0]
+		translations, err := c.translateTexts(ctx, opts.Text, first)
+		if err != nil {
+			return nil, err
+		}
+		result[targetLangs[0]] = translations
+		if len(translations) > 0 && translations[0] != nil {
+			opts.SourceLang = translations[0].DetectedSourceLanguage
+		}
+		remaining = targetLangs[1:]
+	}
+
+	// Every chunk of every target is a job of its own, so that a single pool bounds the concurrency.
+	chunks := chunkCount(len(opts.Text))
+	results := make([][]*Translation, len(remaining))
+	pending := make([]atomic.Int32, len(remaining))
+	for i := range remaining {
+		results[i] = make([]*Translation, len(opts.Text))
+		pending[i].Store(int32(chunks))
+	}
+	err := c.runConcurrently(ctx, len(remaining)*chunks, func(ctx context.Context, job int) error {
+		i := job / chunks
+		targetOpts := opts
+		targetOpts.TargetLang = remaining[i]
+		if err := c.translateChunk(ctx, opts.Text, targetOpts, job%chunks, results[i]); err != nil {
+			return err
+		}
+		pending[i].Add(-1)
+		return nil
+	})
+	if err != nil && !IsQuotaExceeded(err) {
+		return nil, err
+	}
+	for i, targetLang := range remaining {
+		if pending[i].Load() == 0 {
+			result[targetLang] = results[i]
+		}
+	}
+	return result, err
+}
+
+// TranslationTexts returns the translated texts of translations in order, e.g. of the result of
+// TranslateTextWithOptions or TranslationsInOrder. Nil entries yield empty strings.
+func TranslationTexts(translations []*Translation) []string {
+	texts := make([]string, len(translations))
+	for i, translation := range translations {
+		if translation != nil {
+			texts[i] = translation.Text
+		}
+	}
+	return texts
+}
+
+// maxTextsPerRequest is the maximum number of texts DeepL accepts in a single translation request.
+const maxTextsPerRequest = 50
+
+// TranslateTexts translates texts into the target language and returns the translations in the order of texts.
+// Up to 50 texts are sent per request; larger slices are split into several requests, which are issued
+// concurrently, bounded by WithMaxConcurrency.
+// Once DeepL reports that the character limit is reached, no further requests are issued, and the translations
+// finished so far are returned along with the error, for which IsQuotaExceeded reports true. The entries of
+// texts that were not translated are nil.
+// Unless the client sets a default with WithShowBilledCharacters, billed characters are requested, so that
+// TranslationsResponse.TotalBilledCharacters reports the cost of the batch.
+func (c *Client) TranslateTexts(ctx context.Context, texts []string, targetLang string) ([]*Translation, error) {
+	opts := TranslateTextOptions{TargetLang: targetLang}
+	if c.translateDefaults.ShowBilledCharacters == nil {
+		opts.ShowBilledCharacters = True()
+	}
+	return c.translateTexts(ctx, texts, opts)
+}
+
+// translateTexts implements TranslateTexts, translating every chunk of texts with the other fields of opts.
+func (c *Client) translateTexts(ctx context.Context, texts []string, opts TranslateTextOptions) ([]*Translation, error) {
+	if len(texts) == 0 {
+		return []*Translation{}, nil
+	}
+
+	translations := make([]*Translation, len(texts))
+	err := c.runConcurrently(ctx, chunkCount(len(texts)), func(ctx context.Context, i int) error {
+		return c.translateChunk(ctx, texts, opts, i, translations)
+	})
+	if err != nil && !IsQuotaExceeded(err) {
+		return nil, err
+	}
+	return translations, err
+}
+
+// chunkCount returns the number of requests needed to translate n texts.
+func chunkCount(n int) int {
+	return (n + maxTextsPerRequest - 1) / maxTextsPerRequest
+}
+
+// translateChunk translates the chunk with index i of texts, i.e. up to 50 texts starting at
+// i*maxTextsPerRequest, with the other fields of opts and stores the translations at the same positions
+// in translations.
+func (c *Client) translateChunk(ctx context.Context, texts []string, opts TranslateTextOptions, i int, translations []*Translation) error {
+	start := i * maxTextsPerRequest
+	end := start + maxTextsPerRequest
+	if end > len(texts) {
+		end = len(texts)
+	}
+	opts.Text = texts[start:end]
+	result, err := c.TranslateTextWithOptions(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if len(result) != end-start {
+		return errors.New("number of translations does not match number of texts")
+	}
+	copy(translations[start:end], result)
+	return nil
+}
+
+// TranslateMap translates the values of m into the target language and returns a new map with the same keys
+// and the translated values, e.g. to localize an i18n message bundle. The values are sent in batches as
+// by TranslateTexts. Empty values are not sent and are kept empty in the result.
+func (c *Client) TranslateMap(ctx context.Context, m map[string]string, targetLang string) (map[string]string, error) {
+	result := make(map[string]string, len(m))
+	keys := make([]string, 0, len(m))
+	for key, value := range m {
+		if value == "" {
+			result[key] = ""
+			continue
+		}
+		keys = append(keys, key)
+	}
+	// Sort the keys so that the same map always yields the same requests.
+	sort.Strings(keys)
+
+	texts := make([]string, len(keys))
+	for i, key := range keys {
+		texts[i] = m[key]
+	}
+	translations, err := c.TranslateTexts(ctx, texts, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		result[key] = translations[i].Text
+	}
+	return result, nil
+}
+
+// TranslateDelimited translates input consisting of segments separated by delimiter, e.g. records of an
+// export, and returns the translated segments joined with the same delimiter. The segments are sent in
+// batches as by TranslateTexts, using the other fields of opts. Empty and whitespace-only segments are not
+// sent and are kept as they are. The Text and TargetLang fields of opts are ignored.
+func (c *Client) TranslateDelimited(ctx context.Context, input, delimiter, targetLang string, opts TranslateTextOptions) (string, error) {
+	if delimiter == "" {
+		return "", errors.New("delimiter must not be empty")
+	}
+
+	segments := strings.Split(input, delimiter)
+	var indexes []int
+	var texts []string
+	for i, segment := range segments {
+		if strings.TrimSpace(segment) != "" {
+			indexes = append(indexes, i)
+			texts = append(texts, segment)
+		}
+	}
+
+	opts.TargetLang = targetLang
+	translations, err := c.translateTexts(ctx, texts, opts)
+	if err != nil {
+		return "", err
+	}
+	for i, index := range indexes {
+		segments[index] = translations[i].Text
+	}
+	return strings.Join(segments, delimiter), nil
+}
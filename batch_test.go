@@ -0,0 +1,549 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMaxConcurrency(t *testing.T) {
+	if got := NewClient("api-key").maxConcurrency; got != defaultMaxConcurrency {
+		t.Errorf("expected default max concurrency %d, got %d", defaultMaxConcurrency, got)
+	}
+	if got := NewClient("api-key", WithMaxConcurrency(8)).maxConcurrency; got != 8 {
+		t.Errorf("expected max concurrency 8, got %d", got)
+	}
+	if got := NewClient("api-key", WithMaxConcurrency(0)).maxConcurrency; got != 1 {
+		t.Errorf("expected max concurrency to be at least 1, got %d", got)
+	}
+}
+
+func TestRunConcurrently_LimitsInFlightRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+	})
+	client.maxConcurrency = 3
+
+	var completed int32
+	err := client.runConcurrently(context.Background(), 10, func(ctx context.Context, i int) error {
+		if _, err := client.TranslateTextWithContext(ctx, "Hello", "DE"); err != nil {
+			return err
+		}
+		atomic.AddInt32(&completed, 1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if completed != 10 {
+		t.Errorf("expected 10 completed requests, got %d", completed)
+	}
+	if maxInFlight > 3 {
+		t.Errorf("expected at most 3 requests in flight, got %d", maxInFlight)
+	}
+	if maxInFlight < 2 {
+		t.Errorf("expected requests to run concurrently, got at most %d in flight", maxInFlight)
+	}
+}
+
+func TestRunConcurrently_FirstErrorCancelsSiblings(t *testing.T) {
+	client := NewTestClient(nil)
+	client.maxConcurrency = 2
+	errFailed := errors.New("failed")
+
+	var started int32
+	err := client.runConcurrently(context.Background(), 10, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&started, 1)
+		if i == 0 {
+			return errFailed
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	})
+
+	if !errors.Is(err, errFailed) {
+		t.Errorf("expected first error to be returned, got %v", err)
+	}
+	if started > 3 {
+		t.Errorf("expected no further calls after the first error, got %d started", started)
+	}
+}
+
+func TestRunConcurrently_ContextCancelled(t *testing.T) {
+	client := NewTestClient(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.runConcurrently(ctx, 5, func(ctx context.Context, i int) error {
+		t.Error("should not start calls with a cancelled context")
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTranslateUnique(t *testing.T) {
+	texts := []string{"Yes", "No", "Yes", "Cancel", "No"}
+
+	requests := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		body, _ := io.ReadAll(req.Body)
+		var requestData TranslateTextOptions
+		if err := json.Unmarshal(body, &requestData); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !reflect.DeepEqual(requestData.Text, []string{"Yes", "No", "Cancel"}) {
+			t.Errorf("expected only unique texts to be sent, got %q", requestData.Text)
+		}
+
+		var translations []*Translation
+		for _, text := range requestData.Text {
+			translations = append(translations, &Translation{Text: "DE:" + text})
+		}
+		return MockResponse(200, TranslationsResponse{Translations: translations})
+	})
+
+	result, err := client.TranslateUnique(context.Background(), texts, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	if len(result) != 3 || result["No"].Text != "DE:No" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	ordered := TranslationsInOrder(texts, result)
+	var orderedTexts []string
+	for _, translation := range ordered {
+		orderedTexts = append(orderedTexts, translation.Text)
+	}
+	expected := []string{"DE:Yes", "DE:No", "DE:Yes", "DE:Cancel", "DE:No"}
+	if !reflect.DeepEqual(orderedTexts, expected) {
+		t.Errorf("expected %q, got %q", expected, orderedTexts)
+	}
+}
+
+func TestTranslateUnique_Empty(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Error("should not send a request without texts")
+		return nil
+	})
+
+	result, err := client.TranslateUnique(context.Background(), nil, "DE")
+	if err != nil || len(result) != 0 {
+		t.Errorf("expected empty result, got %v and %v", result, err)
+	}
+}
+
+// prefixTranslations returns a mock translating every text into the target language prefixed with it and
+// recording the number of texts per request.
+func prefixTranslations(t *testing.T, mu *sync.Mutex, sizes *[]int) RoundTripFunc {
+	return func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		var requestData TranslateTextOptions
+		if err := json.Unmarshal(body, &requestData); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		mu.Lock()
+		*sizes = append(*sizes, len(requestData.Text))
+		mu.Unlock()
+
+		var translations []*Translation
+		for _, text := range requestData.Text {
+			translations = append(translations, &Translation{DetectedSourceLanguage: "EN", Text: requestData.TargetLang + ":" + text})
+		}
+		return MockResponse(200, TranslationsResponse{Translations: translations})
+	}
+}
+
+func TestTranslateUnique_MoreThan50Texts(t *testing.T) {
+	texts := make([]string, 60)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+	texts = append(texts, texts[:10]...)
+
+	var mu sync.Mutex
+	var sizes []int
+	client := NewTestClient(prefixTranslations(t, &mu, &sizes))
+
+	result, err := client.TranslateUnique(context.Background(), texts, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Ints(sizes)
+	if !reflect.DeepEqual(sizes, []int{10, 50}) {
+		t.Errorf("expected requests of 50 and 10 texts, got %v", sizes)
+	}
+	if len(result) != 60 {
+		t.Fatalf("expected 60 translations, got %d", len(result))
+	}
+	for _, text := range texts {
+		if result[text] == nil || result[text].Text != "DE:"+text {
+			t.Errorf("unexpected translation for %q: %+v", text, result[text])
+		}
+	}
+}
+
+func TestTranslateToTargets_MoreThan50Texts(t *testing.T) {
+	texts := make([]string, 60)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+
+	for _, sourceLang := range []string{"", "EN"} {
+		t.Run("source "+sourceLang, func(t *testing.T) {
+			var mu sync.Mutex
+			var sizes []int
+			client := NewTestClient(prefixTranslations(t, &mu, &sizes))
+
+			result, err := client.TranslateToTargets(context.Background(), TranslateTextOptions{Text: texts, SourceLang: sourceLang}, []string{"DE", "FR"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sort.Ints(sizes)
+			if !reflect.DeepEqual(sizes, []int{10, 10, 50, 50}) {
+				t.Errorf("expected two requests of 50 and 10 texts, got %v", sizes)
+			}
+			for _, targetLang := range []string{"DE", "FR"} {
+				translations := result[targetLang]
+				if len(translations) != len(texts) {
+					t.Fatalf("expected %d translations into %s, got %d", len(texts), targetLang, len(translations))
+				}
+				for i, translation := range translations {
+					if translation == nil || translation.Text != targetLang+":"+texts[i] {
+						t.Errorf("unexpected translation %d into %s: %+v", i, targetLang, translation)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTranslateToTargets_ReusesDetectedSource(t *testing.T) {
+	var mu sync.Mutex
+	sources := make(map[string]string)
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		var requestData TranslateTextOptions
+		if err := json.Unmarshal(body, &requestData); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		mu.Lock()
+		sources[requestData.TargetLang] = requestData.SourceLang
+		mu.Unlock()
+
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{{DetectedSourceLanguage: "EN", Text: requestData.TargetLang + ":Hello"}},
+		})
+	})
+	client.maxConcurrency = 2
+
+	result, err := client.TranslateToTargets(context.Background(), TranslateTextOptions{Text: []string{"Hello"}}, []string{"DE", "FR", "ES"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedSources := map[string]string{"DE": "", "FR": "EN", "ES": "EN"}
+	if !reflect.DeepEqual(sources, expectedSources) {
+		t.Errorf("expected source languages %v, got %v", expectedSources, sources)
+	}
+	for _, target := range []string{"DE", "FR", "ES"} {
+		if len(result[target]) != 1 || result[target][0].Text != target+":Hello" {
+			t.Errorf("unexpected result for %s: %+v", target, result[target])
+		}
+	}
+}
+
+func TestTranslateToTargets_ExplicitSource(t *testing.T) {
+	var requests atomic.Int32
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests.Add(1)
+		body, _ := io.ReadAll(req.Body)
+		var requestData TranslateTextOptions
+		_ = json.Unmarshal(body, &requestData)
+		if requestData.SourceLang != "JA" {
+			t.Errorf("expected explicit source JA, got %q", requestData.SourceLang)
+		}
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{{DetectedSourceLanguage: "JA", Text: "translated"}},
+		})
+	})
+
+	_, err := client.TranslateToTargets(context.Background(), TranslateTextOptions{Text: []string{"こんにちは"}, SourceLang: "JA"}, []string{"DE", "FR"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestTranslationTexts(t *testing.T) {
+	translations := []*Translation{{Text: "Hallo"}, nil, {Text: "Welt"}}
+
+	texts := TranslationTexts(translations)
+	expected := []string{"Hallo", "", "Welt"}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected %q, got %q", expected, texts)
+	}
+
+	if texts := TranslationTexts(nil); len(texts) != 0 {
+		t.Errorf("expected no texts for nil input, got %q", texts)
+	}
+}
+
+func TestTranslateTexts_Chunking(t *testing.T) {
+	texts := make([]string, 120)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+
+	var requests atomic.Int32
+	var mu sync.Mutex
+	var sizes []int
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests.Add(1)
+		body, _ := io.ReadAll(req.Body)
+		var requestData TranslateTextOptions
+		_ = json.Unmarshal(body, &requestData)
+
+		mu.Lock()
+		sizes = append(sizes, len(requestData.Text))
+		mu.Unlock()
+
+		var translations []*Translation
+		for _, text := range requestData.Text {
+			translations = append(translations, &Translation{Text: "DE:" + text})
+		}
+		return MockResponse(200, TranslationsResponse{Translations: translations})
+	})
+
+	translations, err := client.TranslateTexts(context.Background(), texts, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("expected 3 requests for 120 texts, got %d", got)
+	}
+	sort.Ints(sizes)
+	if !reflect.DeepEqual(sizes, []int{20, 50, 50}) {
+		t.Errorf("expected chunks of 50, 50 and 20 texts, got %v", sizes)
+	}
+	if len(translations) != len(texts) {
+		t.Fatalf("expected %d translations, got %d", len(texts), len(translations))
+	}
+	for i, translation := range translations {
+		if translation.Text != "DE:"+texts[i] {
+			t.Errorf("translation %d out of order: %q", i, translation.Text)
+			break
+		}
+	}
+}
+
+func TestTranslateTexts_SingleRequest(t *testing.T) {
+	requests := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}, {Text: "Welt"}}})
+	})
+
+	translations, err := client.TranslateTexts(context.Background(), []string{"Hello", "World"}, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 || len(translations) != 2 {
+		t.Errorf("expected 1 request and 2 translations, got %d and %d", requests, len(translations))
+	}
+}
+
+func TestTranslateTexts_ShowBilledCharacters(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  []Option
+		expected string
+	}{
+		{"requested by default", nil, `"show_billed_characters":true`},
+		{"client default wins", []Option{WithShowBilledCharacters(false)}, `"show_billed_characters":false`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				body, _ := io.ReadAll(req.Body)
+				if !strings.Contains(string(body), tc.expected) {
+					t.Errorf("expected body to contain %s, got %s", tc.expected, body)
+				}
+				return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo", BilledCharacters: 5}}})
+			})
+			for _, opt := range tc.options {
+				opt(client)
+			}
+
+			translations, err := client.TranslateTexts(context.Background(), []string{"Hello"}, "DE")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			response := TranslationsResponse{Translations: translations}
+			if got := response.TotalBilledCharacters(); got != 5 {
+				t.Errorf("expected 5 billed characters, got %d", got)
+			}
+		})
+	}
+}
+
+func TestTranslateMap(t *testing.T) {
+	requests := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		body, _ := io.ReadAll(req.Body)
+		var requestData TranslateTextOptions
+		_ = json.Unmarshal(body, &requestData)
+
+		var translations []*Translation
+		for _, text := range requestData.Text {
+			translations = append(translations, &Translation{Text: "DE:" + text})
+		}
+		return MockResponse(200, TranslationsResponse{Translations: translations})
+	})
+
+	m := map[string]string{
+		"greeting": "Hello",
+		"farewell": "Goodbye",
+		"thanks":   "Thank you",
+		"empty":    "",
+	}
+	result, err := client.TranslateMap(context.Background(), m, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+
+	expected := map[string]string{
+		"greeting": "DE:Hello",
+		"farewell": "DE:Goodbye",
+		"thanks":   "DE:Thank you",
+		"empty":    "",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestTranslateTexts_QuotaExceededAbortsRemainingChunks(t *testing.T) {
+	texts := make([]string, 120)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+
+	var requests atomic.Int32
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if requests.Add(1) == 2 {
+			return MockResponse(456, map[string]string{"message": "Quota exceeded"})
+		}
+		body, _ := io.ReadAll(req.Body)
+		var requestData TranslateTextOptions
+		_ = json.Unmarshal(body, &requestData)
+
+		var translations []*Translation
+		for _, text := range requestData.Text {
+			translations = append(translations, &Translation{Text: "DE:" + text})
+		}
+		return MockResponse(200, TranslationsResponse{Translations: translations})
+	})
+	WithMaxConcurrency(1)(client)
+
+	translations, err := client.TranslateTexts(context.Background(), texts, "DE")
+	if !IsQuotaExceeded(err) {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected the third chunk not to be requested, got %d requests", got)
+	}
+	if len(translations) != len(texts) {
+		t.Fatalf("expected partial results for %d texts, got %d", len(texts), len(translations))
+	}
+	if translations[0] == nil || translations[0].Text != "DE:text 0" {
+		t.Errorf("expected the first chunk to be translated, got %+v", translations[0])
+	}
+	if translations[50] != nil || translations[119] != nil {
+		t.Error("expected the remaining chunks to be untranslated")
+	}
+}
+
+func TestTranslateDelimited(t *testing.T) {
+	var sent []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		var requestData TranslateTextOptions
+		_ = json.Unmarshal(body, &requestData)
+		sent = append(sent, requestData.Text...)
+		if requestData.Formality != "less" {
+			t.Errorf("expected the options to be applied, got formality %q", requestData.Formality)
+		}
+
+		var translations []*Translation
+		for _, text := range requestData.Text {
+			translations = append(translations, &Translation{Text: "DE:" + text})
+		}
+		return MockResponse(200, TranslationsResponse{Translations: translations})
+	})
+
+	result, err := client.TranslateDelimited(context.Background(), "Hello||World|Bye", "|", "DE", TranslateTextOptions{FormalityLevel: FormalityLess})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "DE:Hello||DE:World|DE:Bye" {
+		t.Errorf("expected 'DE:Hello||DE:World|DE:Bye', got %q", result)
+	}
+	if !reflect.DeepEqual(sent, []string{"Hello", "World", "Bye"}) {
+		t.Errorf("expected empty segments not to be sent, got %q", sent)
+	}
+}
+
+func TestTranslateDelimited_EmptyDelimiter(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Error("should not send a request without a delimiter")
+		return nil
+	})
+
+	if _, err := client.TranslateDelimited(context.Background(), "Hello", "", "DE", TranslateTextOptions{}); err == nil {
+		t.Error("expected error for an empty delimiter")
+	}
+}
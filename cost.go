@@ -0,0 +1,23 @@
+package deepl
+
+import "unicode/utf8"
+
+// EstimateCost returns the cost of translating chars characters at the given price per million characters,
+// in whatever currency the price is stated. DeepL's rates depend on the plan, so the price is left to the caller.
+func EstimateCost(chars int, pricePerMillion float64) float64 {
+	if chars <= 0 || pricePerMillion <= 0 {
+		return 0
+	}
+	return float64(chars) * pricePerMillion / 1_000_000
+}
+
+// EstimateTranslationCost returns the estimated cost of translating opts at the given price per million characters.
+// Like DeepL, it counts the characters of all texts to translate, while the context is free of charge.
+// The actual bill may differ slightly, e.g. if DeepL strips markup; use ShowBilledCharacters for exact numbers.
+func EstimateTranslationCost(opts TranslateTextOptions, pricePerMillion float64) float64 {
+	chars := 0
+	for _, text := range opts.Text {
+		chars += utf8.RuneCountInString(text)
+	}
+	return EstimateCost(chars, pricePerMillion)
+}
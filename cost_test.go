@@ -0,0 +1,45 @@
+package deepl
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	testCases := []struct {
+		name            string
+		chars           int
+		pricePerMillion float64
+		expected        float64
+	}{
+		{"zero characters", 0, 20, 0},
+		{"one million characters", 1_000_000, 20, 20},
+		{"half a million characters", 500_000, 25, 12.5},
+		{"small text", 1_234, 20, 0.02468},
+		{"negative characters", -10, 20, 0},
+		{"zero price", 1_000, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := EstimateCost(tc.chars, tc.pricePerMillion)
+			if math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestEstimateTranslationCost(t *testing.T) {
+	opts := TranslateTextOptions{
+		Text:       []string{"Hello world", "Grüße"},
+		TargetLang: "DE",
+		Context:    "This context is not billed.",
+	}
+
+	// 11 + 5 characters, counted as runes rather than bytes.
+	got := EstimateTranslationCost(opts, 1_000_000)
+	if got != 16 {
+		t.Errorf("expected cost 16, got %v", got)
+	}
+}
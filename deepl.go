@@ -3,29 +3,53 @@ package deepl
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
 	baseURL     = "https://api.deepl.com"
 	baseURLFree = "https://api-free.deepl.com"
 	version     = "0.3.0"
+
+	defaultAuthScheme = "DeepL-Auth-Key" // Scheme of the Authorization header expected by DeepL
+)
+
+// JitterStrategy selects how the exponential retry backoff is randomized.
+type JitterStrategy int8
+
+const (
+	// JitterFull waits a random duration between zero and the exponential delay (default).
+	JitterFull JitterStrategy = iota
+	// JitterEqual waits half the exponential delay plus a random duration of up to the other half,
+	// avoiding near-zero delays.
+	JitterEqual
 )
 
 type retryPolicy struct {
 	MaxRetries  int
 	MaxDelay    time.Duration
 	BackoffBase time.Duration
+	Jitter      JitterStrategy
 }
 
 var defaultRetryPolicy = retryPolicy{
@@ -36,11 +60,33 @@ var defaultRetryPolicy = retryPolicy{
 
 // Client represents a DeepL API client.
 type Client struct {
-	apiKey      string       // API authentication key
-	baseURL     string       // Base URL for API endpoints (depends on API key type)
-	userAgent   string       // User-Agent header value sent with requests
-	httpClient  *http.Client // Underlying HTTP client used for requests
-	retryPolicy retryPolicy  // retryPolicy represents the retry logic configuration including maximum retries and maximum delay duration.
+	apiKey             string                                // API authentication key
+	baseURL            string                                // Base URL for API endpoints (depends on API key type)
+	userAgent          string                                // User-Agent header value sent with requests
+	httpClient         *http.Client                          // Underlying HTTP client used for requests
+	retryPolicy        retryPolicy                           // retryPolicy represents the retry logic configuration including maximum retries and maximum delay duration.
+	validationMode     ValidationMode                        // How strictly request options are checked before sending
+	logf               func(format string, args ...any)      // Logger used for advisory warnings and traces
+	maxConcurrency     int                                   // Maximum number of concurrent requests issued by batch helpers
+	requireSourceLang  bool                                  // Whether translations must not rely on source language auto-detection
+	authScheme         string                                // Scheme preceding the API key in the Authorization header
+	billedCharacters   atomic.Int64                          // Running total of characters billed for translations
+	testMode           bool                                  // Whether client-side guards are relaxed for mock servers
+	baseContext        context.Context                       // Client-wide context whose cancellation aborts all requests
+	configErr          error                                 // Error from an invalid option, returned by every request
+	languages          *languageCache                        // Cached language lists, nil unless WithLanguageCache is used
+	glossaries         glossaryCache                         // Glossary list cached for TranslateTextWithBestGlossary
+	baseTransport      http.RoundTripper                     // Transport below the tracing and middlewares, nil until one is set
+	timeout            *time.Duration                        // Timeout set by WithTimeout, applied to any HTTP client, nil if unset
+	proxy              func(*http.Request) (*url.URL, error) // Proxy set by WithProxy, applied to the base transport, nil if unset
+	trace              *loggingRoundTripper                  // Tracing settings of WithTrace, nil if disabled
+	middlewares        []Middleware                          // Middlewares added by WithMiddleware, outermost first
+	responseValidator  func(*http.Response) error            // Custom check of successful responses, nil if unset
+	retryOnDecodeError bool                                  // Whether successful responses that fail to decode are requested again
+	rateLimiter        *rateLimiter                          // Token bucket limiting the request rate, nil if unlimited
+	adaptiveRateLimit  bool                                  // Whether 429 responses throttle the rate limiter, see WithAdaptiveRateLimit
+	normalization      *norm.Form                            // Unicode normalization applied to translated texts, nil if disabled
+	translateDefaults  translateDefaults                     // Client defaults of the boolean translation options
 }
 
 // Option defines a functional option for configuring the DeepL Client.
@@ -54,9 +100,15 @@ func NewClient(apiKey string, opts ...Option) *Client {
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
-		baseURL:     getBaseURL(apiKey),
-		userAgent:   "deepl-go/" + version,
-		retryPolicy: defaultRetryPolicy,
+		baseURL:        getBaseURL(apiKey),
+		userAgent:      "deepl-go/" + version,
+		retryPolicy:    defaultRetryPolicy,
+		logf:           log.Printf,
+		maxConcurrency: defaultMaxConcurrency,
+		authScheme:     defaultAuthScheme,
+	}
+	for _, opt := range currentDefaultOptions() {
+		opt(client)
 	}
 	for _, opt := range opts {
 		opt(client)
@@ -64,6 +116,48 @@ func NewClient(apiKey string, opts ...Option) *Client {
 	return client
 }
 
+// DiagnosticInfo returns the configuration of the client for bug reports, e.g. the base URL, user agent,
+// account type, retry policy and timeout. It never includes the API key or other credentials, so the
+// result can be shared safely.
+func (c *Client) DiagnosticInfo() map[string]string {
+	accountType := "pro"
+	if strings.HasSuffix(c.apiKey, ":fx") {
+		accountType = "free"
+	}
+	return map[string]string{
+		"version":            version,
+		"base_url":           c.baseURL,
+		"user_agent":         c.userAgent,
+		"account_type":       accountType,
+		"max_retries":        strconv.Itoa(c.retryPolicy.MaxRetries),
+		"max_retry_delay":    c.retryPolicy.MaxDelay.String(),
+		"retry_backoff_base": c.retryPolicy.BackoffBase.String(),
+		"timeout":            c.httpClient.Timeout.String(),
+	}
+}
+
+var (
+	defaultOptionsMu sync.RWMutex
+	defaultOptions   []Option
+)
+
+// SetDefaultOptions sets options that every subsequent NewClient call applies before its own options,
+// so that a per-client option overrides a default one. Calling it again replaces the previous defaults,
+// and calling it without arguments clears them. Clients created earlier are not affected.
+// It is meant to be called once during program initialisation, although it is safe for concurrent use.
+func SetDefaultOptions(opts ...Option) {
+	defaultOptionsMu.Lock()
+	defer defaultOptionsMu.Unlock()
+	defaultOptions = append([]Option(nil), opts...)
+}
+
+// currentDefaultOptions returns a snapshot of the options set by SetDefaultOptions.
+func currentDefaultOptions() []Option {
+	defaultOptionsMu.RLock()
+	defer defaultOptionsMu.RUnlock()
+	return defaultOptions
+}
+
 // WithUserAgent returns an Option that sets the User-Agent header for HTTP requests.
 func WithUserAgent(userAgent string) Option {
 	return func(c *Client) {
@@ -71,56 +165,317 @@ func WithUserAgent(userAgent string) Option {
 	}
 }
 
+// WithTimeout returns an Option that sets the timeout of each HTTP request made by the client, including
+// reading the response body. The default is 60 seconds, and zero disables the timeout.
+// The timeout applies to every attempt separately, so a retried request may take longer in total. It also
+// applies to the methods without a context parameter; a deadline of the context passed to a call still
+// ends the request earlier if it is shorter.
+// The timeout also applies to a client supplied by WithHTTPClient, regardless of the order of the options.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = &d
+		c.httpClient.Timeout = d
+	}
+}
+
 // WithProxy returns an Option that configures the client to use the specified proxy URL.
+// The proxy is set on the transport of the client, also if it is supplied by WithHTTPClient; see WithHTTPClient
+// for the requirements on that transport.
 func WithProxy(proxy url.URL) Option {
 	return func(c *Client) {
-		c.httpClient.Transport = &http.Transport{
-			Proxy: http.ProxyURL(&proxy),
-		}
+		c.setProxy(http.ProxyURL(&proxy))
+	}
+}
+
+// WithProxyFromEnvironment returns an Option that configures the client to use the proxy given by the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables (or their lowercase versions).
+func WithProxyFromEnvironment() Option {
+	return func(c *Client) {
+		c.setProxy(http.ProxyFromEnvironment)
 	}
 }
 
 // WithRetryPolicy returns an Option that sets the maximum retry attempts and maximum delay for retrying failed requests.
+// The backoff before the first retry stays at 500ms and doubles with every further attempt up to the maximum delay.
 func WithRetryPolicy(maxRetryAttempts, maxDelaySeconds int) Option {
 	return func(c *Client) {
+		backoffBase := c.retryPolicy.BackoffBase
+		if backoffBase <= 0 {
+			backoffBase = defaultRetryPolicy.BackoffBase
+		}
 		c.retryPolicy = retryPolicy{
-			MaxRetries: maxRetryAttempts,
-			MaxDelay:   time.Duration(maxDelaySeconds) * time.Second,
+			MaxRetries:  maxRetryAttempts,
+			MaxDelay:    time.Duration(maxDelaySeconds) * time.Second,
+			BackoffBase: backoffBase,
+			Jitter:      c.retryPolicy.Jitter,
 		}
 	}
 }
 
+// WithNoRetry returns an Option that disables retries, so that every request is sent once and a failure,
+// including 429 and 5xx responses, is returned immediately.
+func WithNoRetry() Option {
+	return func(c *Client) {
+		c.retryPolicy.MaxRetries = 0
+	}
+}
+
+// WithAuthScheme returns an Option that sets the scheme preceding the API key in the Authorization header,
+// e.g. "Bearer" for gateways expecting token authentication. The default is "DeepL-Auth-Key".
+func WithAuthScheme(scheme string) Option {
+	return func(c *Client) {
+		c.authScheme = scheme
+	}
+}
+
+// WithJitterStrategy returns an Option that sets how retry delays are randomized. The default is JitterFull.
+func WithJitterStrategy(strategy JitterStrategy) Option {
+	return func(c *Client) {
+		c.retryPolicy.Jitter = strategy
+	}
+}
+
+// WithRetryOnDecodeError returns an Option that sets whether a successful response whose JSON body cannot be
+// decoded, e.g. because the connection dropped mid-body, is requested again. Such retries follow the retry
+// policy and count against its maximum attempts. Requests that must not be repeated, such as document
+// uploads, are never retried this way. It is disabled by default.
+func WithRetryOnDecodeError(enabled bool) Option {
+	return func(c *Client) {
+		c.retryOnDecodeError = enabled
+	}
+}
+
 // WithBaseURL returns an Option that sets a custom base URL for the client.
 // This is particularly useful for testing with mock servers or using alternative API endpoints.
-func WithBaseURL(baseURL string) Option {
+// It always overrides the endpoint NewClient infers from the API key, which assumes the free API for keys
+// ending in ":fx" and the pro API otherwise. For keys that do not follow this convention, pass
+// "https://api-free.deepl.com" or "https://api.deepl.com" explicitly.
+// A trailing slash is removed. If rawURL is not an absolute http or https URL, every request
+// made by the client fails with an error describing the invalid URL.
+func WithBaseURL(rawURL string) Option {
 	return func(c *Client) {
-		c.baseURL = baseURL
+		u, err := url.Parse(rawURL)
+		if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+			err = errors.New("must be an absolute http or https URL")
+		}
+		if err != nil {
+			c.configErr = fmt.Errorf("%w %q: %w", errInvalidBaseURL, rawURL, err)
+			return
+		}
+		c.baseURL = strings.TrimRight(rawURL, "/")
+		if errors.Is(c.configErr, errInvalidBaseURL) {
+			c.configErr = nil
+		}
 	}
 }
 
+// Errors from invalid options, stored in configErr. Each option only clears its own error, so that a valid
+// option does not hide an earlier invalid one.
+var (
+	errInvalidBaseURL       = errors.New("invalid base URL")
+	errProxyUnsupportedBase = errors.New("cannot set a proxy")
+)
+
+// defaultTraceBodyLimit is the number of body bytes logged per request or response when tracing is enabled.
+const defaultTraceBodyLimit = 4 * 1024
+
 // WithTrace returns an Option that enables HTTP request and response logging for debugging.
+// Bodies longer than 4 KiB are truncated in the log; use WithTraceBodyLimit to change the limit.
 func WithTrace() Option {
+	return WithTraceBodyLimit(defaultTraceBodyLimit)
+}
+
+// WithTraceBodyLimit returns an Option that enables HTTP request and response logging like WithTrace,
+// logging at most maxBodyBytes of each body, e.g. to keep document uploads from flooding the log.
+// A value of zero or less logs bodies in full.
+func WithTraceBodyLimit(maxBodyBytes int) Option {
 	return func(c *Client) {
-		prev := c.httpClient.Transport
-		if prev == nil {
-			prev = http.DefaultTransport
+		c.trace = &loggingRoundTripper{MaxBodyBytes: maxBodyBytes}
+		c.rebuildTransport()
+	}
+}
+
+// WithLogger returns an Option that sets the function receiving the log output of the client, i.e. the
+// request and response dumps of WithTrace and advisory warnings, e.g. to route them into a structured logger.
+// The default is log.Printf; a nil logf restores it.
+func WithLogger(logf func(format string, args ...any)) Option {
+	return func(c *Client) {
+		if logf == nil {
+			logf = log.Printf
+		}
+		c.logf = logf
+	}
+}
+
+// Middleware wraps the transport of the client, e.g. to record metrics or inject headers.
+// It receives the next RoundTripper in the chain and returns one that calls it.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// WithMiddleware returns an Option that adds middlewares to the transport of the client.
+//
+// The transport is composed in a fixed order regardless of the order of the options: middlewares see
+// a request first, in the order they were added, followed by the tracing of WithTrace, and finally
+// the base transport, which carries the proxy settings of WithProxy. Each request therefore passes
+// every middleware once, and the trace logs the request as it is sent.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+		c.rebuildTransport()
+	}
+}
+
+// rebuildTransport composes the transport of the client from its base transport, the proxy, the tracing, and
+// the middlewares as documented in WithMiddleware. The first call takes the current transport as the base.
+// The proxy is set on a clone of the base transport, so that its other settings, such as the TLS configuration,
+// are kept. If the base transport is not an *http.Transport, it is left as it is and every request fails
+// with an error instead of bypassing the proxy.
+func (c *Client) rebuildTransport() {
+	if c.baseTransport == nil {
+		c.baseTransport = c.httpClient.Transport
+		if c.baseTransport == nil {
+			c.baseTransport = http.DefaultTransport
 		}
-		c.httpClient.Transport = &loggingRoundTripper{
-			Proxied: prev,
+	}
+
+	rt := c.baseTransport
+	if errors.Is(c.configErr, errProxyUnsupportedBase) {
+		c.configErr = nil
+	}
+	if c.proxy != nil {
+		if base, ok := rt.(*http.Transport); ok {
+			transport := base.Clone()
+			transport.Proxy = c.proxy
+			rt = transport
+		} else {
+			c.configErr = fmt.Errorf("%w: the transport %T is not an *http.Transport", errProxyUnsupportedBase, rt)
 		}
 	}
+	if c.trace != nil {
+		rt = &loggingRoundTripper{Proxied: rt, MaxBodyBytes: c.trace.MaxBodyBytes, Logf: c.warnf}
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		rt = c.middlewares[i](rt)
+	}
+	c.httpClient.Transport = rt
+}
+
+// WithResponseValidator returns an Option that calls validate with every successful (2xx) response before
+// its body is decoded, e.g. to require certain headers. If validate returns an error, the call fails with
+// an error wrapping it. The body must not be consumed by validate.
+func WithResponseValidator(validate func(*http.Response) error) Option {
+	return func(c *Client) {
+		c.responseValidator = validate
+	}
+}
+
+// WithClientContext returns an Option that ties all requests of the client to ctx, e.g. for graceful shutdown.
+// Once ctx is cancelled, requests in flight are aborted and subsequent requests fail immediately.
+// The context passed to each call still applies, so a request ends when either of the two is done.
+func WithClientContext(ctx context.Context) Option {
+	return func(c *Client) {
+		c.baseContext = ctx
+	}
+}
+
+// WithHTTPClient returns an Option that sends requests with a copy of hc, e.g. for custom timeouts, connection
+// pooling, or an instrumented transport. The transport of hc becomes the base transport as documented in
+// WithMiddleware, so WithProxy, WithTrace and WithMiddleware apply to it regardless of the order of the options.
+// Likewise, a timeout set by WithTimeout replaces the timeout of hc.
+// A proxy requires the transport of hc to be nil or an *http.Transport; otherwise every request fails with an
+// error. A nil hc is ignored.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc == nil {
+			return
+		}
+		client := *hc
+		c.httpClient = &client
+		if c.timeout != nil {
+			c.httpClient.Timeout = *c.timeout
+		}
+		c.baseTransport = nil
+		if c.proxy != nil || c.trace != nil || len(c.middlewares) > 0 {
+			c.rebuildTransport()
+		}
+	}
+}
+
+// setProxy sets the proxy function that rebuildTransport applies to the base transport.
+func (c *Client) setProxy(proxy func(*http.Request) (*url.URL, error)) {
+	c.proxy = proxy
+	c.rebuildTransport()
 }
 
 // doRequest sends an HTTP request using the client's configuration, applies authentication and content headers,
 // performs the request with retry logic, and decodes the JSON response body into the provided interface.
-// It returns any error encountered during the request or decoding process.
+// It returns any error encountered during the request or decoding process. Non-success responses yield an *APIError,
+// while network and decoding errors carry no HTTP status.
+// If enabled by WithRetryOnDecodeError, a response that fails to decode is requested again.
 func (c *Client) doRequest(ctx context.Context, req *http.Request, v any) error {
-	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
-	req.Header.Set("Content-Type", "application/json")
+	maxRetries := 0
+	if c.retryOnDecodeError && isIdempotent(req) {
+		maxRetries = c.retryPolicy.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		decodeFailed := false
+		err := c.doRequestRaw(ctx, req, func(body io.Reader) error {
+			if err := json.NewDecoder(body).Decode(v); err != nil {
+				decodeFailed = true
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+			return nil
+		})
+		if !decodeFailed || attempt >= maxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(calculateRetryDelay(attempt, c.retryPolicy)):
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled during retry: %w", ctx.Err())
+		}
+	}
+}
+
+// doRequestRaw sends the request like doRequest but passes the body of a successful response to handle
+// instead of decoding it as JSON, e.g. to stream a translated document. The body is closed afterwards.
+// Content-Type and Accept headers already set on req are kept, so that an endpoint can exchange other
+// formats such as multipart uploads or TSV; otherwise both default to JSON.
+func (c *Client) doRequestRaw(ctx context.Context, req *http.Request, handle func(body io.Reader) error) error {
+	return c.doRequestResponse(ctx, req, func(resp *http.Response) error {
+		return handle(resp.Body)
+	})
+}
+
+// setRequestHeaders sets the authorization and the default content headers every request to DeepL carries.
+func (c *Client) setRequestHeaders(req *http.Request) {
+	authScheme := c.authScheme
+	if authScheme == "" {
+		authScheme = defaultAuthScheme
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", authScheme, c.apiKey))
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
 	if c.userAgent != "" {
 		req.Header.Set("User-Agent", c.userAgent)
 	}
+}
+
+// doRequestResponse sends the request like doRequestRaw but passes the whole successful response to handle,
+// e.g. to inspect its headers. The body is closed afterwards.
+func (c *Client) doRequestResponse(ctx context.Context, req *http.Request, handle func(resp *http.Response) error) error {
+	if c.configErr != nil {
+		return c.configErr
+	}
+	ctx, cancel := c.withBaseContext(ctx)
+	defer cancel()
 
+	c.setRequestHeaders(req)
 	resp, respErr := c.performRetryableRequest(ctx, req)
 
 	if respErr != nil {
@@ -129,10 +484,44 @@ func (c *Client) doRequest(ctx context.Context, req *http.Request, v any) error
 
 	defer func() { _ = resp.Body.Close() }()
 
-	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
-		return err
+	if c.responseValidator != nil {
+		if err := c.responseValidator(resp); err != nil {
+			return fmt.Errorf("response validation failed: %w", err)
+		}
+	}
+
+	return handle(resp)
+}
+
+// withBaseContext returns a context that is done when either ctx or the client context set by WithClientContext
+// is done. The returned cancel function releases the resources and must be called once the request finished.
+func (c *Client) withBaseContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.baseContext == nil {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	if err := c.baseContext.Err(); err != nil {
+		cancel(err)
+		return ctx, func() { cancel(nil) }
+	}
+	go func() {
+		select {
+		case <-c.baseContext.Done():
+			cancel(c.baseContext.Err())
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() { cancel(nil) }
+}
+
+// doJSON sends the request like doRequest and decodes the JSON response body into a newly allocated T.
+// It avoids declaring a response variable at every call site and passing its address around.
+func doJSON[T any](c *Client, ctx context.Context, req *http.Request) (*T, error) {
+	var v T
+	if err := c.doRequest(ctx, req, &v); err != nil {
+		return nil, err
 	}
-	return nil
+	return &v, nil
 }
 
 // performRetryableRequest executes an HTTP request with retry logic based on the client's retry policy.
@@ -140,7 +529,16 @@ func (c *Client) performRetryableRequest(ctx context.Context, req *http.Request)
 	var resp *http.Response
 	var respErr error
 
-	for attempt := 0; attempt <= c.retryPolicy.MaxRetries; attempt++ {
+	idempotent := isIdempotent(req)
+	maxRetries := effectiveMaxRetries(ctx, c.retryPolicy)
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.wait(ctx); err != nil {
+				return nil, fmt.Errorf("context cancelled while waiting for rate limit: %w", err)
+			}
+		}
+
 		cloneReq, err := cloneRequest(req)
 		if err != nil {
 			return nil, fmt.Errorf("failed to clone request: %w", err)
@@ -148,13 +546,24 @@ func (c *Client) performRetryableRequest(ctx context.Context, req *http.Request)
 
 		cloneReq = cloneReq.WithContext(ctx)
 		resp, respErr = c.httpClient.Do(cloneReq)
-		shouldRetry, delay := c.shouldRetry(resp, respErr, attempt)
-		if !shouldRetry {
+		if respErr == nil {
+			c.adaptRateLimit(resp)
+		}
+		shouldRetry, delay := c.shouldRetry(resp, respErr, attempt, idempotent)
+		if !shouldRetry || attempt == maxRetries {
+			break
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			// The next attempt could not start before the deadline, so report the current result instead.
 			break
 		}
 
 		select {
 		case <-time.After(delay):
+			// A zero delay may win the race against a cancelled context, so check it before the next attempt.
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("context cancelled during retry: %w", ctx.Err())
+			}
 			continue // continue to next attempt
 		case <-ctx.Done():
 			return nil, fmt.Errorf("context cancelled during retry: %w", ctx.Err())
@@ -165,7 +574,7 @@ func (c *Client) performRetryableRequest(ctx context.Context, req *http.Request)
 		return nil, respErr
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		return nil, createErrorFromResponse(resp)
 	}
 
@@ -174,49 +583,244 @@ func (c *Client) performRetryableRequest(ctx context.Context, req *http.Request)
 
 // errorResponse represents the error message returned by the DeepL API in JSON format.
 type errorResponse struct {
-	Message string `json:"message"` // Human-readable error message
+	Message string          `json:"message"`        // Human-readable error message
+	Code    json.RawMessage `json:"code,omitempty"` // Machine-readable error code, if any, as a string or number
 }
 
-// createErrorFromResponse generates an error describing the HTTP response including status and message if available.
+// createErrorFromResponse generates an *APIError describing the HTTP response including status and message if available.
 func createErrorFromResponse(resp *http.Response) error {
 	defer func() { _ = resp.Body.Close() }()
-	statusText := "unknown error"
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		TraceID:    resp.Header.Get("X-Trace-ID"),
+		statusText: "unknown error",
+	}
 	if resp.StatusCode == 456 {
-		statusText = "character limit has been reached"
+		apiErr.statusText = "character limit has been reached"
 	} else if http.StatusText(resp.StatusCode) != "" {
-		statusText = strings.ToLower(http.StatusText(resp.StatusCode))
+		apiErr.statusText = strings.ToLower(http.StatusText(resp.StatusCode))
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("HTTP %d %s; error reading the body: %w", resp.StatusCode, statusText, err)
+		apiErr.bodyErr = err
+		return apiErr
 	}
 
 	var errResp errorResponse
-	err = json.NewDecoder(bytes.NewReader(bodyBytes)).Decode(&errResp)
-	if err == nil && errResp.Message != "" {
-		return fmt.Errorf("HTTP %d %s: %s", resp.StatusCode, statusText, errResp.Message)
+	if err := json.NewDecoder(bytes.NewReader(bodyBytes)).Decode(&errResp); err == nil {
+		apiErr.Message = errResp.Message
+		apiErr.Code = errorCode(errResp.Code)
 	}
 
-	return fmt.Errorf("HTTP %d %s", resp.StatusCode, statusText)
+	return apiErr
+}
+
+// errorCode returns the error code of an error response as a string, whether it was sent as a JSON string or number.
+func errorCode(raw json.RawMessage) string {
+	var code string
+	if err := json.Unmarshal(raw, &code); err == nil {
+		return code
+	}
+	if raw == nil || string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
 }
 
-// shouldRetry examines the error message and returns true if it's retryable
-func (c *Client) shouldRetry(resp *http.Response, err error, attempt int) (shouldRetry bool, delay time.Duration) {
-	if err != nil || resp.StatusCode == 429 || resp.StatusCode >= 500 {
-		return true, calculateRetryDelay(attempt, c.retryPolicy)
+// shouldRetry examines the error message and returns true if it's retryable.
+// Non-idempotent requests are only retried on 429, where the server rejected the request without processing it.
+func (c *Client) shouldRetry(resp *http.Response, err error, attempt int, idempotent bool) (shouldRetry bool, delay time.Duration) {
+	if !idempotent {
+		if err == nil && resp.StatusCode == 429 {
+			return true, c.retryDelay(resp, attempt)
+		}
+		return false, 0
+	}
+	if err != nil {
+		if isRetryableTransportError(err) {
+			return true, calculateRetryDelay(attempt, c.retryPolicy)
+		}
+		return false, 0
+	}
+	if resp.StatusCode == 429 || resp.StatusCode >= 500 {
+		return true, c.retryDelay(resp, attempt)
 	}
 	return false, 0
 }
 
+// isRetryableTransportError reports whether a request that failed without an HTTP response is worth repeating.
+// Connection resets and other transient network failures are retried. Context cancellations and deadlines,
+// timeouts of the HTTP client, unknown hosts, and certificate errors are not, as repeating the request cannot
+// succeed or would only extend a wait the caller has already bounded.
+func isRetryableTransportError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+
+	var (
+		certVerifyErr   *tls.CertificateVerificationError
+		unknownAuthErr  x509.UnknownAuthorityError
+		certInvalidErr  x509.CertificateInvalidError
+		hostnameErr     x509.HostnameError
+		recordHeaderErr tls.RecordHeaderError
+	)
+	if errors.As(err, &certVerifyErr) || errors.As(err, &unknownAuthErr) || errors.As(err, &certInvalidErr) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &recordHeaderErr) {
+		return false
+	}
+
+	return true
+}
+
+// statusOverloaded is the non-standard status DeepL returns when it is temporarily overloaded.
+const statusOverloaded = 529
+
+// retryDelay returns the backoff before retrying after resp. If a 429, 503 or 529 response carries a
+// Retry-After header, the delay is at least the time it asks for, capped at the policy's MaxDelay.
+func (c *Client) retryDelay(resp *http.Response, attempt int) time.Duration {
+	delay := calculateRetryDelay(attempt, c.retryPolicy)
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, statusOverloaded:
+	default:
+		return delay
+	}
+	retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok || retryAfter <= delay {
+		return delay
+	}
+	if retryAfter > c.retryPolicy.MaxDelay {
+		return c.retryPolicy.MaxDelay
+	}
+	return retryAfter
+}
+
+// parseRetryAfter parses the value of a Retry-After header, given either in seconds or as an HTTP date
+// relative to now. It reports false if the value is missing or malformed.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := date.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
 // calculateRetryDelay returns a randomized backoff duration with exponential growth capped at maxDelay.
 func calculateRetryDelay(attempt int, policy retryPolicy) time.Duration {
+	expDelay := exponentialDelay(attempt, policy)
+	if policy.Jitter == JitterEqual {
+		// jitter between expDelay/2 and expDelay
+		half := expDelay / 2
+		return half + time.Duration(rand.Int63n(int64(expDelay-half)+1))
+	}
+	// jitter between 0 and expDelay
+	return time.Duration(rand.Int63n(int64(expDelay) + 1))
+}
+
+// averageRetryDelay returns the expected value of calculateRetryDelay for the given attempt.
+func averageRetryDelay(attempt int, policy retryPolicy) time.Duration {
+	expDelay := exponentialDelay(attempt, policy)
+	if policy.Jitter == JitterEqual {
+		return expDelay * 3 / 4
+	}
+	return expDelay / 2
+}
+
+// exponentialDelay returns the upper bound of the backoff before the given retry attempt, capped at maxDelay.
+func exponentialDelay(attempt int, policy retryPolicy) time.Duration {
 	expDelay := time.Duration(math.Pow(2, float64(attempt))) * policy.BackoffBase
 	if expDelay > policy.MaxDelay {
 		expDelay = policy.MaxDelay
 	}
-	// jitter between 0 and expDelay
-	return time.Duration(rand.Int63n(int64(expDelay) + 1))
+	return expDelay
+}
+
+// effectiveMaxRetries caps the policy's MaxRetries so that the average backoff of all planned retries fits
+// before the context deadline. Without a deadline, the policy's MaxRetries is returned unchanged.
+func effectiveMaxRetries(ctx context.Context, policy retryPolicy) int {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return policy.MaxRetries
+	}
+
+	remaining := time.Until(deadline)
+	retries := 0
+	for retries < policy.MaxRetries {
+		remaining -= averageRetryDelay(retries, policy)
+		if remaining <= 0 {
+			break
+		}
+		retries++
+	}
+	return retries
+}
+
+// Retry calls fn up to attempts times until it succeeds, waiting between attempts with the exponential
+// backoff and jitter of the client's retry policy. It is meant for composite operations such as uploading,
+// waiting for and downloading a document, whose single requests are retried already but which may fail
+// as a whole. Every error returned by fn is retried, so fn should only be wrapped if repeating it is safe.
+// The last error of fn is returned, or the context's error if ctx is done while waiting.
+// fn is always called at least once, also if attempts is less than 1.
+func (c *Client) Retry(ctx context.Context, attempts int, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt == attempts-1 {
+			break
+		}
+
+		select {
+		case <-time.After(calculateRetryDelay(attempt, c.retryPolicy)):
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled during retry: %w", ctx.Err())
+		}
+	}
+	return err
+}
+
+// nonIdempotentKey is the context key marking a request that must not be repeated once the server may have processed it.
+type nonIdempotentKey struct{}
+
+// markNonIdempotent returns a shallow copy of req marked as non-idempotent, e.g. a document upload
+// whose repetition would create a duplicate document. Such requests are retried conservatively.
+func markNonIdempotent(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), nonIdempotentKey{}, true))
+}
+
+// isIdempotent reports whether req may be safely repeated after a server-side failure.
+// Requests are idempotent unless marked otherwise, as the JSON endpoints of the DeepL API can be repeated safely.
+func isIdempotent(req *http.Request) bool {
+	marked, _ := req.Context().Value(nonIdempotentKey{}).(bool)
+	return !marked
 }
 
 // cloneRequest creates a deep copy of the *http.Request including the body.
@@ -249,7 +853,9 @@ func getBaseURL(apiKey string) string {
 
 // loggingRoundTripper is an http.RoundTripper that logs HTTP requests and responses.
 type loggingRoundTripper struct {
-	Proxied http.RoundTripper
+	Proxied      http.RoundTripper
+	MaxBodyBytes int                              // Maximum number of body bytes logged, no limit if zero or less
+	Logf         func(format string, args ...any) // Logger receiving the dumps, log.Printf if nil
 }
 
 // RoundTrip implements the RoundTripper interface.
@@ -257,28 +863,78 @@ type loggingRoundTripper struct {
 func (lrt *loggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	reqDump, err := httputil.DumpRequestOut(req, true)
 	if err != nil {
-		log.Printf("error dumping request: %v", err)
+		lrt.logf("error dumping request: %v", err)
 	} else {
-		log.Printf("HTTP Request:\n%s", string(reqDump))
+		lrt.logf("HTTP Request:\n%s", lrt.truncateDump(redactCredentials(reqDump)))
 	}
 
 	res, err := lrt.Proxied.RoundTrip(req)
 	if err != nil {
-		log.Printf("error during round trip: %v", err)
+		lrt.logf("error during round trip: %v", err)
 		return nil, err
 	}
 
 	resDump, err := httputil.DumpResponse(res, true)
 	if err != nil {
-		log.Printf("error dumping response: %v", err)
+		lrt.logf("error dumping response: %v", err)
 	} else {
-		log.Printf("HTTP Response:\n%s", string(resDump))
+		lrt.logf("HTTP Response:\n%s", lrt.truncateDump(resDump))
 	}
 
 	return res, nil
 }
 
+// logf logs through the configured logger, falling back to the standard logger.
+func (lrt *loggingRoundTripper) logf(format string, args ...any) {
+	if lrt.Logf != nil {
+		lrt.Logf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// authorizationPattern matches the credentials of an Authorization header line, keeping the scheme in group 2.
+var authorizationPattern = regexp.MustCompile(`(?im)^(Authorization:[ \t]*)(\S+[ \t]+)?\S+`)
+
+// authKeyPattern matches the value of an auth_key parameter in a query string or form body.
+var authKeyPattern = regexp.MustCompile(`\bauth_key=[^&\s]*`)
+
+// redactCredentials returns the dumped request with the API key in the Authorization header and in any
+// auth_key parameter replaced by "***", so that traces can be logged without leaking the key.
+func redactCredentials(dump []byte) []byte {
+	dump = authorizationPattern.ReplaceAll(dump, []byte("${1}${2}***"))
+	return authKeyPattern.ReplaceAll(dump, []byte("auth_key=***"))
+}
+
+// truncateDump returns the dumped message with its body cut to MaxBodyBytes, followed by a marker
+// stating how many bytes were left out. The header section is always kept in full.
+func (lrt *loggingRoundTripper) truncateDump(dump []byte) string {
+	if lrt.MaxBodyBytes <= 0 {
+		return string(dump)
+	}
+	headerEnd := bytes.Index(dump, []byte("\r\n\r\n"))
+	if headerEnd < 0 {
+		return string(dump)
+	}
+	bodyStart := headerEnd + len("\r\n\r\n")
+	omitted := len(dump) - bodyStart - lrt.MaxBodyBytes
+	if omitted <= 0 {
+		return string(dump)
+	}
+	return fmt.Sprintf("%s...[truncated %d bytes]", dump[:bodyStart+lrt.MaxBodyBytes], omitted)
+}
+
 // BoolPtr is a helper function that returns a pointer to a bool value.
 func BoolPtr(b bool) *bool {
 	return &b
 }
+
+// True returns a pointer to true, e.g. for TranslateTextOptions.PreserveFormatting.
+func True() *bool {
+	return BoolPtr(true)
+}
+
+// False returns a pointer to false, e.g. for TranslateTextOptions.OutlineDetection.
+func False() *bool {
+	return BoolPtr(false)
+}
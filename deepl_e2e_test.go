@@ -76,6 +76,7 @@ func createTestClient(serverURL string) *deepl.Client {
 	return deepl.NewClient(mockAPIKey,
 		deepl.WithBaseURL(serverURL),
 		deepl.WithUserAgent(testUserAgent),
+		deepl.WithTestMode(),
 	)
 }
 
@@ -165,6 +166,21 @@ func TestE2E_DeepLClient_GetUsage(t *testing.T) {
 		usage.CharacterCount, usage.CharacterLimit)
 }
 
+func TestE2E_DeepLClient_IsMockServer(t *testing.T) {
+	serverURL := getMockServerURL()
+	waitForMockServer(t, serverURL)
+
+	client := createTestClient(serverURL)
+
+	mock, err := client.IsMockServer(context.Background())
+	if err != nil {
+		t.Fatalf("IsMockServer should succeed with mock server, got error: %v", err)
+	}
+	if !mock {
+		t.Error("IsMockServer should detect the mock server")
+	}
+}
+
 func TestE2E_DeepLClient_TranslateText(t *testing.T) {
 	serverURL := getMockServerURL()
 	waitForMockServer(t, serverURL)
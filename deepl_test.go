@@ -1,14 +1,24 @@
 package deepl
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"reflect"
 	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -16,7 +26,20 @@ import (
 type RoundTripFunc func(req *http.Request) *http.Response
 
 func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
-	return f(req), nil
+	resp := f(req)
+	if resp == nil {
+		// A nil response means the mock gave up on the request, e.g. because its context was cancelled.
+		return nil, req.Context().Err()
+	}
+	return resp, nil
+}
+
+// RoundTripErrFunc is the variant of RoundTripFunc for mocks that fail with an error, e.g. a network error,
+// or pass the request on to another transport.
+type RoundTripErrFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripErrFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
 }
 
 func NewTestClient(fn RoundTripFunc) *Client {
@@ -82,6 +105,103 @@ func TestWithUserAgent(t *testing.T) {
 	}
 }
 
+func TestWithTimeout(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		select {
+		case <-req.Context().Done():
+			return nil
+		case <-time.After(time.Second):
+			return MockResponse(http.StatusOK, TranslationsResponse{})
+		}
+	})
+	WithTimeout(time.Millisecond)(client)
+
+	_, err := client.TranslateText("Hello", "DE")
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+}
+
+func TestWithTimeout_ComposesWithHTTPClient(t *testing.T) {
+	hc := &http.Client{Timeout: time.Minute}
+	testCases := map[string][]Option{
+		"timeout first":     {WithTimeout(5 * time.Second), WithHTTPClient(hc)},
+		"HTTP client first": {WithHTTPClient(hc), WithTimeout(5 * time.Second)},
+	}
+
+	for name, opts := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := NewClient("api-key", opts...)
+			if client.httpClient.Timeout != 5*time.Second {
+				t.Errorf("expected timeout 5s, got %v", client.httpClient.Timeout)
+			}
+		})
+	}
+	if hc.Timeout != time.Minute {
+		t.Errorf("expected the supplied client to be left unchanged, got timeout %v", hc.Timeout)
+	}
+	if got := NewClient("api-key", WithHTTPClient(hc)).httpClient.Timeout; got != time.Minute {
+		t.Errorf("expected the timeout of the supplied client without WithTimeout, got %v", got)
+	}
+}
+
+func TestDiagnosticInfo(t *testing.T) {
+	client := NewClient("secret-api-key:fx", WithUserAgent("custom-agent"), WithTimeout(30*time.Second))
+
+	info := client.DiagnosticInfo()
+	for key, value := range info {
+		if strings.Contains(value, "secret-api-key") {
+			t.Errorf("expected the API key not to be included, found it in %q", key)
+		}
+	}
+
+	expected := map[string]string{
+		"base_url":     baseURLFree,
+		"user_agent":   "custom-agent",
+		"account_type": "free",
+		"max_retries":  "5",
+		"timeout":      "30s",
+	}
+	for key, value := range expected {
+		if info[key] != value {
+			t.Errorf("expected %s %q, got %q", key, value, info[key])
+		}
+	}
+	for _, key := range []string{"version", "max_retry_delay", "retry_backoff_base"} {
+		if info[key] == "" {
+			t.Errorf("expected %s to be set", key)
+		}
+	}
+}
+
+func TestSetDefaultOptions(t *testing.T) {
+	SetDefaultOptions(WithUserAgent("default-agent"), WithAuthScheme("Bearer"))
+	t.Cleanup(func() { SetDefaultOptions() })
+
+	client := NewClient("api-key")
+	if client.userAgent != "default-agent" {
+		t.Errorf("expected default userAgent 'default-agent', got %s", client.userAgent)
+	}
+	if client.authScheme != "Bearer" {
+		t.Errorf("expected default authScheme 'Bearer', got %s", client.authScheme)
+	}
+
+	client = NewClient("api-key", WithUserAgent("custom-agent"))
+	if client.userAgent != "custom-agent" {
+		t.Errorf("expected per-client userAgent 'custom-agent', got %s", client.userAgent)
+	}
+	if client.authScheme != "Bearer" {
+		t.Errorf("expected default authScheme 'Bearer' to remain, got %s", client.authScheme)
+	}
+
+	SetDefaultOptions()
+	client = NewClient("api-key")
+	if client.userAgent != "deepl-go/"+version {
+		t.Errorf("expected cleared defaults to restore userAgent, got %s", client.userAgent)
+	}
+}
+
 func TestWithBaseURL(t *testing.T) {
 	customBaseURL := "http://localhost:8080"
 	client := NewClient("api-key", WithBaseURL(customBaseURL))
@@ -91,6 +211,43 @@ func TestWithBaseURL(t *testing.T) {
 	}
 }
 
+func TestWithBaseURL_TrimsTrailingSlash(t *testing.T) {
+	var requestURL string
+	client := NewClient("api-key", WithBaseURL("http://localhost:3000/"))
+	client.httpClient.Transport = RoundTripFunc(func(req *http.Request) *http.Response {
+		requestURL = req.URL.String()
+		return MockResponse(200, map[string]int{"character_count": 1, "character_limit": 10})
+	})
+
+	if _, err := client.GetUsage(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requestURL != "http://localhost:3000/v2/usage" {
+		t.Errorf("expected request to 'http://localhost:3000/v2/usage', got %s", requestURL)
+	}
+}
+
+func TestWithBaseURL_Invalid(t *testing.T) {
+	for _, rawURL := range []string{"localhost:3000", "ftp://example.com", "://missing-scheme", "/v2"} {
+		t.Run(rawURL, func(t *testing.T) {
+			requests := 0
+			client := NewClient("api-key", WithBaseURL(rawURL))
+			client.httpClient.Transport = RoundTripFunc(func(req *http.Request) *http.Response {
+				requests++
+				return MockResponse(200, nil)
+			})
+
+			_, err := client.GetUsage()
+			if err == nil || !strings.Contains(err.Error(), "invalid base URL") {
+				t.Errorf("expected invalid base URL error, got %v", err)
+			}
+			if requests != 0 {
+				t.Errorf("expected no request to be sent, got %d", requests)
+			}
+		})
+	}
+}
+
 func TestWithBaseURL_OverridesDefaultBehavior(t *testing.T) {
 	// Test that WithBaseURL overrides the default API key based URL selection
 	customBaseURL := "https://custom-api.example.com"
@@ -108,17 +265,498 @@ func TestWithBaseURL_OverridesDefaultBehavior(t *testing.T) {
 	}
 }
 
-func TestWithProxy(t *testing.T) {
-	proxyUrl, _ := url.Parse("http://localhost:8080")
-	client := NewClient("api-key", WithProxy(*proxyUrl))
+func TestWithBaseURL_OverridesInferredEndpoint(t *testing.T) {
+	// A free key without the ":fx" suffix looks like a pro key, so the endpoint must be set explicitly.
+	client := NewClient("enterprise-api-key", WithBaseURL(baseURLFree))
+	if client.baseURL != baseURLFree {
+		t.Errorf("expected baseURL %s, got %s", baseURLFree, client.baseURL)
+	}
+
+	// Defaults are applied after the inference as well, before the options of the call.
+	SetDefaultOptions(WithBaseURL(baseURLFree))
+	t.Cleanup(func() { SetDefaultOptions() })
+	client = NewClient("enterprise-api-key")
+	if client.baseURL != baseURLFree {
+		t.Errorf("expected default option to override the inferred endpoint, got %s", client.baseURL)
+	}
+	client = NewClient("enterprise-api-key", WithBaseURL(baseURL))
+	if client.baseURL != baseURL {
+		t.Errorf("expected the option of the call to win over the default, got %s", client.baseURL)
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	proxyUrl, _ := url.Parse("http://localhost:8080")
+	client := NewClient("api-key", WithProxy(*proxyUrl))
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected http.Transport but got %T", client.httpClient.Transport)
+	}
+
+	if transport.Proxy == nil {
+		t.Error("expected proxy function to be set")
+	}
+}
+
+func TestWithProxyFromEnvironment(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://proxy.example.com:3128")
+	t.Setenv("NO_PROXY", "localhost")
+
+	client := NewClient("api-key", WithProxyFromEnvironment())
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected http.Transport but got %T", client.httpClient.Transport)
+	}
+
+	if transport.Proxy == nil {
+		t.Fatal("expected proxy function to be set")
+	}
+
+	if reflect.ValueOf(transport.Proxy).Pointer() != reflect.ValueOf(http.ProxyFromEnvironment).Pointer() {
+		t.Error("expected proxy function to be http.ProxyFromEnvironment")
+	}
+}
+
+func TestWithProxyFromEnvironment_KeepsTLSConfig(t *testing.T) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS13}
+	client := NewClient("api-key")
+	client.httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+
+	WithProxyFromEnvironment()(client)
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected http.Transport but got %T", client.httpClient.Transport)
+	}
+
+	if transport.TLSClientConfig == nil || transport.TLSClientConfig.MinVersion != tls.VersionTLS13 {
+		t.Error("expected TLS configuration to be kept")
+	}
+}
+
+func TestWithTrace_TruncatesLargeBodies(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, map[string]string{"message": "ok"})
+	})
+	WithTrace()(client)
+
+	body := strings.Repeat("a", 10*1024)
+	req, _ := http.NewRequest(http.MethodPost, "https://api.deepl.com/some-endpoint", strings.NewReader(body))
+	var er errorResponse
+	if err := client.doRequest(context.Background(), req, &er); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := logs.String()
+	if !strings.Contains(out, fmt.Sprintf("...[truncated %d bytes]", len(body)-defaultTraceBodyLimit)) {
+		t.Errorf("expected truncation marker in log, got %q", out)
+	}
+	if strings.Contains(out, body) {
+		t.Error("expected request body to be truncated in log")
+	}
+	if !strings.Contains(out, `{"message":"ok"}`) {
+		t.Errorf("expected small response body to be logged in full, got %q", out)
+	}
+}
+
+func TestWithTraceBodyLimit_NoLimit(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, map[string]string{"message": "ok"})
+	})
+	WithTraceBodyLimit(0)(client)
+
+	body := strings.Repeat("a", 10*1024)
+	req, _ := http.NewRequest(http.MethodPost, "https://api.deepl.com/some-endpoint", strings.NewReader(body))
+	var er errorResponse
+	if err := client.doRequest(context.Background(), req, &er); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := logs.String()
+	if !strings.Contains(out, body) || strings.Contains(out, "[truncated") {
+		t.Error("expected request body to be logged in full")
+	}
+}
+
+func TestWithLogger_ReceivesTrace(t *testing.T) {
+	var stdLogs bytes.Buffer
+	log.SetOutput(&stdLogs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	var logs []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+	})
+	WithTrace()(client)
+	WithLogger(func(format string, args ...any) {
+		logs = append(logs, fmt.Sprintf(format, args...))
+	})(client)
+
+	if _, err := client.TranslateText("Hello", "DE"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := strings.Join(logs, "\n")
+	if !strings.Contains(out, "HTTP Request:") || !strings.Contains(out, "/v2/translate") {
+		t.Errorf("expected request dump through the logger, got %q", out)
+	}
+	if !strings.Contains(out, "HTTP Response:") || !strings.Contains(out, "Hallo") {
+		t.Errorf("expected response dump through the logger, got %q", out)
+	}
+	if stdLogs.Len() > 0 {
+		t.Errorf("expected nothing to be written to the standard logger, got %q", stdLogs.String())
+	}
+}
+
+func TestWithTrace_RedactsAPIKey(t *testing.T) {
+	var logs []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.Header.Get("Authorization") != "DeepL-Auth-Key test-api-key" {
+			t.Errorf("expected the request to keep its Authorization header, got %q", req.Header.Get("Authorization"))
+		}
+		return MockResponse(200, Usage{CharacterCount: 1, CharacterLimit: 100})
+	})
+	WithTrace()(client)
+	WithLogger(func(format string, args ...any) {
+		logs = append(logs, fmt.Sprintf(format, args...))
+	})(client)
+
+	if _, err := client.GetUsage(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := strings.Join(logs, "\n")
+	if strings.Contains(out, "test-api-key") {
+		t.Errorf("expected the API key to be redacted, got %q", out)
+	}
+	if !strings.Contains(out, "Authorization: DeepL-Auth-Key ***") {
+		t.Errorf("expected redacted Authorization header in log, got %q", out)
+	}
+}
+
+func TestRedactCredentials(t *testing.T) {
+	testCases := []struct {
+		dump     string
+		expected string
+	}{
+		{
+			"POST /v2/usage HTTP/1.1\r\nAuthorization: Bearer secret\r\n\r\n",
+			"POST /v2/usage HTTP/1.1\r\nAuthorization: Bearer ***\r\n\r\n",
+		},
+		{
+			"POST /v2/usage HTTP/1.1\r\nauthorization: secret\r\n\r\n",
+			"POST /v2/usage HTTP/1.1\r\nauthorization: ***\r\n\r\n",
+		},
+		{
+			"GET /v2/usage?auth_key=secret&type=target HTTP/1.1\r\n\r\ntext=Hi&auth_key=secret",
+			"GET /v2/usage?auth_key=***&type=target HTTP/1.1\r\n\r\ntext=Hi&auth_key=***",
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := string(redactCredentials([]byte(tc.dump))); got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestWithMiddleware_ComposesWithTraceAndProxy(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		_, _ = w.Write([]byte(`{"character_count": 1, "character_limit": 10}`))
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	var metrics []string
+	metricsMiddleware := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripErrFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err == nil {
+				metrics = append(metrics, fmt.Sprintf("%s %d", req.URL.Path, resp.StatusCode))
+			}
+			return resp, err
+		})
+	}
+
+	// Proxy is applied after trace and middleware and must not discard either of them.
+	client := NewClient("api-key",
+		WithBaseURL("http://api.deepl.test"),
+		WithMiddleware(metricsMiddleware),
+		WithTrace(),
+		WithProxy(*proxyURL),
+	)
+
+	if _, err := client.GetUsage(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if proxiedHost != "api.deepl.test" {
+		t.Errorf("expected request to pass the proxy for api.deepl.test, got host %q", proxiedHost)
+	}
+	if !strings.Contains(logs.String(), "HTTP Request:") || !strings.Contains(logs.String(), "HTTP Response:") {
+		t.Errorf("expected request and response to be traced, got %q", logs.String())
+	}
+	if len(metrics) != 1 || metrics[0] != "/v2/usage 200" {
+		t.Errorf("expected the middleware to record the request once, got %v", metrics)
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	var requests int
+	transport := RoundTripErrFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return MockResponse(200, map[string]int{"character_count": 1, "character_limit": 10}), nil
+	})
+	hc := &http.Client{Transport: transport, Timeout: 7 * time.Second}
+
+	client := NewClient("api-key", WithHTTPClient(hc))
+	if client.httpClient.Timeout != 7*time.Second {
+		t.Errorf("expected timeout of the custom client to survive, got %v", client.httpClient.Timeout)
+	}
+	if _, err := client.GetUsage(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the custom transport to send 1 request, got %d", requests)
+	}
+}
+
+func TestWithHTTPClient_ComposesWithTrace(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	testCases := []struct {
+		name    string
+		options func(hc *http.Client) []Option
+	}{
+		{"trace after client", func(hc *http.Client) []Option { return []Option{WithHTTPClient(hc), WithTrace()} }},
+		{"trace before client", func(hc *http.Client) []Option { return []Option{WithTrace(), WithHTTPClient(hc)} }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs.Reset()
+			var requests int
+			transport := RoundTripErrFunc(func(req *http.Request) (*http.Response, error) {
+				requests++
+				return MockResponse(200, map[string]int{"character_count": 1, "character_limit": 10}), nil
+			})
+			hc := &http.Client{Transport: transport, Timeout: 7 * time.Second}
+
+			client := NewClient("api-key", tc.options(hc)...)
+			if _, ok := client.httpClient.Transport.(*loggingRoundTripper); !ok {
+				t.Fatalf("expected the trace to wrap the custom transport, got %T", client.httpClient.Transport)
+			}
+			if client.httpClient.Timeout != 7*time.Second {
+				t.Errorf("expected timeout of the custom client to survive, got %v", client.httpClient.Timeout)
+			}
+			if _, ok := hc.Transport.(RoundTripErrFunc); !ok {
+				t.Errorf("expected the custom client not to be modified, got transport %T", hc.Transport)
+			}
+
+			if _, err := client.GetUsage(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if requests != 1 {
+				t.Errorf("expected the custom transport to send 1 request, got %d", requests)
+			}
+			if !strings.Contains(logs.String(), "HTTP Request:") {
+				t.Errorf("expected the request to be traced, got %q", logs.String())
+			}
+		})
+	}
+}
+
+func TestWithHTTPClient_ComposesWithProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		_, _ = w.Write([]byte(`{"character_count": 1, "character_limit": 10}`))
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	testCases := []struct {
+		name    string
+		options func(hc *http.Client) []Option
+	}{
+		{"proxy after client", func(hc *http.Client) []Option { return []Option{WithHTTPClient(hc), WithProxy(*proxyURL)} }},
+		{"proxy before client", func(hc *http.Client) []Option { return []Option{WithProxy(*proxyURL), WithHTTPClient(hc)} }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			proxiedHost = ""
+			tlsConfig := &tls.Config{MinVersion: tls.VersionTLS13}
+			hc := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}, Timeout: 7 * time.Second}
+
+			client := NewClient("api-key", append(tc.options(hc), WithBaseURL("http://api.deepl.test"))...)
+			transport, ok := client.httpClient.Transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("expected http.Transport but got %T", client.httpClient.Transport)
+			}
+			if transport.TLSClientConfig == nil || transport.TLSClientConfig.MinVersion != tls.VersionTLS13 {
+				t.Error("expected the settings of the injected transport to be kept")
+			}
+			if hc.Transport.(*http.Transport).Proxy != nil {
+				t.Error("expected the custom client not to be modified")
+			}
+
+			if _, err := client.GetUsage(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if proxiedHost != "api.deepl.test" {
+				t.Errorf("expected request to pass the proxy for api.deepl.test, got host %q", proxiedHost)
+			}
+		})
+	}
+}
+
+func TestWithHTTPClient_ProxyUnsupportedTransport(t *testing.T) {
+	proxyURL, _ := url.Parse("http://localhost:8080")
+	testCases := []struct {
+		name    string
+		options func(hc *http.Client) []Option
+	}{
+		{"proxy after client", func(hc *http.Client) []Option { return []Option{WithHTTPClient(hc), WithProxy(*proxyURL)} }},
+		{"proxy before client", func(hc *http.Client) []Option { return []Option{WithProxy(*proxyURL), WithHTTPClient(hc)} }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int
+			transport := RoundTripErrFunc(func(req *http.Request) (*http.Response, error) {
+				requests++
+				return MockResponse(200, map[string]int{"character_count": 1, "character_limit": 10}), nil
+			})
+			client := NewClient("api-key", tc.options(&http.Client{Transport: transport})...)
+
+			if _, ok := client.httpClient.Transport.(RoundTripErrFunc); !ok {
+				t.Errorf("expected the injected transport to be kept, got %T", client.httpClient.Transport)
+			}
+			_, err := client.GetUsage()
+			if err == nil || !strings.Contains(err.Error(), "cannot set a proxy") {
+				t.Errorf("expected proxy error, got %v", err)
+			}
+			if requests != 0 {
+				t.Errorf("expected no request to be sent, got %d", requests)
+			}
+		})
+	}
+}
+
+func TestWithMiddleware_Order(t *testing.T) {
+	var calls []string
+	named := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripErrFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls = append(calls, "transport")
+		return MockResponse(200, map[string]string{"message": "ok"})
+	})
+	WithMiddleware(named("first"))(client)
+	WithMiddleware(named("second"))(client)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.deepl.com/some-endpoint", nil)
+	var er errorResponse
+	if err := client.doRequest(context.Background(), req, &er); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(calls, ",") != "first,second,transport" {
+		t.Errorf("expected middlewares in the order added, got %v", calls)
+	}
+}
+
+func TestWithResponseValidator(t *testing.T) {
+	errMissingRequestID := errors.New("missing X-Request-Id header")
+	validator := func(resp *http.Response) error {
+		if resp.Header.Get("X-Request-Id") == "" {
+			return errMissingRequestID
+		}
+		return nil
+	}
+
+	withHeader := false
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		resp := MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+		if withHeader {
+			resp.Header.Set("X-Request-Id", "abc")
+		}
+		return resp
+	})
+	WithResponseValidator(validator)(client)
+
+	_, err := client.TranslateText("Hello", "DE")
+	if !errors.Is(err, errMissingRequestID) {
+		t.Errorf("expected the validator error, got %v", err)
+	}
+
+	withHeader = true
+	translation, err := client.TranslateText("Hello", "DE")
+	if err != nil {
+		t.Fatalf("unexpected error with valid response: %v", err)
+	}
+	if translation.Text != "Hallo" {
+		t.Errorf("expected 'Hallo', got %q", translation.Text)
+	}
+}
+
+func TestWithResponseValidator_SkipsErrorResponses(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(403, map[string]string{"message": "Forbidden"})
+	})
+	WithResponseValidator(func(*http.Response) error {
+		t.Error("validator should not run for error responses")
+		return nil
+	})(client)
+
+	_, err := client.GetUsage()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 403 {
+		t.Errorf("expected *APIError with status 403, got %v", err)
+	}
+}
+
+func TestWithAuthScheme(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if got := req.Header.Get("Authorization"); got != "Bearer test-api-key" {
+			t.Errorf("expected Authorization header 'Bearer test-api-key', got %s", got)
+		}
+		return MockResponse(200, map[string]string{})
+	})
+	WithAuthScheme("Bearer")(client)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.deepl.com/some-endpoint", nil)
+	var resp map[string]string
 
-	transport, ok := client.httpClient.Transport.(*http.Transport)
-	if !ok {
-		t.Fatalf("expected http.Transport but got %T", client.httpClient.Transport)
+	if err := client.doRequest(context.Background(), req, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if transport.Proxy == nil {
-		t.Error("expected proxy function to be set")
+	if NewClient("api-key").authScheme != "DeepL-Auth-Key" {
+		t.Error("expected default auth scheme DeepL-Auth-Key")
 	}
 }
 
@@ -156,6 +794,41 @@ func TestSendRequest(t *testing.T) {
 	}
 }
 
+func TestDoJSON(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{{DetectedSourceLanguage: "EN", Text: "Hallo Welt"}},
+		})
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.deepl.com/v2/translate", strings.NewReader(`{"text":["Hello world"],"target_lang":"DE"}`))
+
+	resp, err := doJSON[TranslationsResponse](client, context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Translations) != 1 || resp.Translations[0].Text != "Hallo Welt" {
+		t.Errorf("unexpected translations: %+v", resp.Translations)
+	}
+}
+
+func TestDoJSONWithErrorStatus(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(403, nil)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.deepl.com/v2/translate", nil)
+
+	resp, err := doJSON[TranslationsResponse](client, context.Background(), req)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if resp != nil {
+		t.Errorf("expected nil response on error, got %+v", resp)
+	}
+}
+
 func TestSendRequestWithErrorStatus(t *testing.T) {
 	testCases := []struct {
 		statusCode    int
@@ -223,6 +896,146 @@ func TestSendRequestWithJSONDecodeError(t *testing.T) {
 	}
 }
 
+func TestWithRetryOnDecodeError(t *testing.T) {
+	testCases := []struct {
+		name             string
+		enabled          bool
+		expectedRequests int
+	}{
+		{"enabled", true, 2},
+		{"disabled", false, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				requests++
+				if requests == 1 {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"translations":[{"text":"Hal`)),
+						Header:     make(http.Header),
+					}
+				}
+				return MockResponse(http.StatusOK, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+			})
+			client.retryPolicy = retryPolicy{MaxRetries: 3, MaxDelay: time.Second, BackoffBase: time.Millisecond}
+			WithRetryOnDecodeError(tc.enabled)(client)
+
+			result, err := client.TranslateText("Hello", "DE")
+			if requests != tc.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tc.expectedRequests, requests)
+			}
+			if !tc.enabled {
+				if err == nil || !strings.Contains(err.Error(), "failed to decode response") {
+					t.Fatalf("expected decode error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Text != "Hallo" {
+				t.Errorf("expected 'Hallo', got %q", result.Text)
+			}
+		})
+	}
+}
+
+func TestWithRetryOnDecodeError_NonIdempotentRequest(t *testing.T) {
+	requests := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"document_id":`)),
+			Header:     make(http.Header),
+		}
+	})
+	client.retryPolicy = retryPolicy{MaxRetries: 3, MaxDelay: time.Second, BackoffBase: time.Millisecond}
+	WithRetryOnDecodeError(true)(client)
+
+	_, err := client.UploadDocument(strings.NewReader("Hello"), "hello.txt", "DE", nil)
+	if err == nil {
+		t.Fatal("expected decode error")
+	}
+	if requests != 1 {
+		t.Errorf("expected the upload not to be repeated, got %d requests", requests)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	client := NewTestClient(nil)
+	client.retryPolicy = retryPolicy{MaxRetries: 3, MaxDelay: 10 * time.Millisecond, BackoffBase: time.Millisecond}
+	transient := errors.New("transient failure")
+
+	calls := 0
+	err := client.Retry(context.Background(), 5, func() error {
+		calls++
+		if calls < 3 {
+			return transient
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success after transient failures, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+
+	calls = 0
+	err = client.Retry(context.Background(), 2, func() error {
+		calls++
+		return transient
+	})
+	if !errors.Is(err, transient) {
+		t.Errorf("expected the last error after all attempts, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetry_NoAttempts(t *testing.T) {
+	client := NewTestClient(nil)
+	failure := errors.New("failure")
+
+	for _, attempts := range []int{0, -1} {
+		calls := 0
+		err := client.Retry(context.Background(), attempts, func() error {
+			calls++
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			t.Errorf("attempts %d: expected the error of fn, got %v", attempts, err)
+		}
+		if calls != 1 {
+			t.Errorf("attempts %d: expected fn to be called once, got %d calls", attempts, calls)
+		}
+	}
+}
+
+func TestRetry_ContextCancelled(t *testing.T) {
+	client := NewTestClient(nil)
+	client.retryPolicy = retryPolicy{MaxRetries: 3, MaxDelay: time.Minute, BackoffBase: time.Minute, Jitter: JitterEqual}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := client.Retry(ctx, 3, func() error {
+		calls++
+		cancel()
+		return errors.New("transient failure")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no further call after cancellation, got %d calls", calls)
+	}
+}
+
 func TestGetBaseURL(t *testing.T) {
 	testCases := []struct {
 		apiKey      string
@@ -269,6 +1082,51 @@ func TestSendRequestWithRetry_RetryOn429ThenSuccess(t *testing.T) {
 	}
 }
 
+func TestSendRequestWithRetry_Overloaded529(t *testing.T) {
+	testCases := []struct {
+		name             string
+		noRetry          bool
+		expectedAttempts int
+	}{
+		{"retried", false, 2},
+		{"with no retry", true, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				attempts++
+				if attempts == 1 {
+					resp := MockResponse(529, map[string]string{"message": "Too many requests"})
+					resp.Header.Set("Retry-After", "0")
+					return resp
+				}
+				return MockResponse(200, Usage{CharacterCount: 1, CharacterLimit: 100})
+			})
+			client.retryPolicy = retryPolicy{MaxRetries: 3, MaxDelay: time.Second, BackoffBase: time.Millisecond}
+			if tc.noRetry {
+				WithNoRetry()(client)
+			}
+
+			_, err := client.GetUsage()
+			if attempts != tc.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tc.expectedAttempts, attempts)
+			}
+			if tc.noRetry {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != 529 {
+					t.Errorf("expected *APIError with status 529, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expected success after retry, got %v", err)
+			}
+		})
+	}
+}
+
 func TestSendRequestWithRetry_ExceedsMaxRetries(t *testing.T) {
 	attempt := 0
 	client := NewTestClient(func(req *http.Request) *http.Response {
@@ -310,6 +1168,193 @@ func TestSendRequestWithRetry_DoNotRetryOnOtherError(t *testing.T) {
 	}
 }
 
+func TestSendRequestWithRetry_TransportErrors(t *testing.T) {
+	testCases := []struct {
+		name             string
+		err              error
+		expectedAttempts int
+	}{
+		{"connection reset retried", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, 3},
+		{"unexpected EOF retried", io.ErrUnexpectedEOF, 3},
+		{"context deadline not retried", context.DeadlineExceeded, 1},
+		{"context cancel not retried", context.Canceled, 1},
+		{"client timeout not retried", &url.Error{Op: "Post", URL: "https://api.deepl.com", Err: timeoutError{}}, 1},
+		{"unknown host not retried", &net.DNSError{Err: "no such host", Name: "api.deepl.invalid", IsNotFound: true}, 1},
+		{"unknown authority not retried", x509.UnknownAuthorityError{}, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempt := 0
+			client := NewTestClient(nil)
+			client.httpClient.Transport = RoundTripErrFunc(func(req *http.Request) (*http.Response, error) {
+				attempt++
+				return nil, tc.err
+			})
+			client.retryPolicy = retryPolicy{MaxRetries: 2, MaxDelay: 10 * time.Millisecond}
+
+			req, _ := http.NewRequest(http.MethodPost, "https://api.deepl.com/some-endpoint", nil)
+			var er errorResponse
+
+			err := client.doRequest(context.Background(), req, &er)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if attempt != tc.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tc.expectedAttempts, attempt)
+			}
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout awaiting response headers" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestWithClientContext_AbortsInFlightRequest(t *testing.T) {
+	clientCtx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	var once sync.Once
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		once.Do(func() { close(started) })
+		<-req.Context().Done()
+		return nil
+	})
+	WithClientContext(clientCtx)(client)
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := client.TranslateText("Hello", "DE")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled after cancelling the client context, got %v", err)
+	}
+
+	_, err = client.TranslateText("Hello", "DE")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected subsequent request to fail with context.Canceled, got %v", err)
+	}
+}
+
+func TestWithClientContext_CallContextStillApplies(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		<-req.Context().Done()
+		return nil
+	})
+	WithClientContext(context.Background())(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.TranslateTextWithContext(ctx, "Hello", "DE")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded from the call context, got %v", err)
+	}
+}
+
+func TestShouldRetry_RetryAfter(t *testing.T) {
+	client := NewTestClient(nil)
+	client.retryPolicy = retryPolicy{MaxRetries: 3, MaxDelay: 10 * time.Second, BackoffBase: 10 * time.Millisecond}
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{"seconds on 429", 429, "2", 2 * time.Second, 2 * time.Second},
+		{"seconds on 503", 503, "2", 2 * time.Second, 2 * time.Second},
+		{"seconds on 529", 529, "3", 3 * time.Second, 3 * time.Second},
+		{"http date", 429, time.Now().Add(3 * time.Second).UTC().Format(http.TimeFormat), 1 * time.Second, 3 * time.Second},
+		{"capped by max delay", 429, "120", 10 * time.Second, 10 * time.Second},
+		{"ignored on 500", 500, "2", 0, 10 * time.Millisecond},
+		{"unparsable", 429, "soon", 0, 10 * time.Millisecond},
+		{"absent", 429, "", 0, 10 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := MockResponse(tc.statusCode, nil)
+			if tc.retryAfter != "" {
+				resp.Header.Set("Retry-After", tc.retryAfter)
+			}
+
+			retry, delay := client.shouldRetry(resp, nil, 0, true)
+			if !retry {
+				t.Fatal("expected the response to be retried")
+			}
+			if delay < tc.min || delay > tc.max {
+				t.Errorf("expected delay between %v and %v, got %v", tc.min, tc.max, delay)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"2", 2 * time.Second, true},
+		{" 0 ", 0, true},
+		{"-1", 0, false},
+		{"Wed, 01 May 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 May 2024 11:59:00 GMT", 0, true},
+		{"tomorrow", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := parseRetryAfter(tc.value, now)
+		if got != tc.expected || ok != tc.ok {
+			t.Errorf("parseRetryAfter(%q): expected (%v, %v), got (%v, %v)", tc.value, tc.expected, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestDoRequestRaw_ReturnsBodyAfterRetry(t *testing.T) {
+	attempt := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		attempt++
+		if attempt == 1 {
+			return MockResponse(503, map[string]string{"message": "service unavailable"})
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("Cart\tWarenkorb\nCheckout\tKasse\n")),
+			Header:     http.Header{"Content-Type": {"text/tab-separated-values"}},
+		}
+	})
+	client.retryPolicy = retryPolicy{MaxRetries: 2, MaxDelay: 10 * time.Millisecond}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.deepl.com/v2/glossaries/g1/entries", nil)
+	req.Header.Set("Accept", "text/tab-separated-values")
+
+	var body []byte
+	err := client.doRequestRaw(context.Background(), req, func(r io.Reader) error {
+		var err error
+		body, err = io.ReadAll(r)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("expected success after retry, got %v", err)
+	}
+	if attempt != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempt)
+	}
+	if string(body) != "Cart\tWarenkorb\nCheckout\tKasse\n" {
+		t.Errorf("expected the TSV body intact, got %q", body)
+	}
+}
+
 func TestSendRequestWithRetry_ContextCancel(t *testing.T) {
 	attempt := 0
 	client := NewTestClient(func(req *http.Request) *http.Response {
@@ -336,3 +1381,141 @@ func TestSendRequestWithRetry_ContextCancel(t *testing.T) {
 		t.Fatalf("expected at least one attempt before cancellation, got %d", attempt)
 	}
 }
+
+func TestSendRequestWithRetry_NonIdempotentRequest(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statusCode       int
+		nonIdempotent    bool
+		expectedAttempts int
+	}{
+		{"idempotent retried on 503", 503, false, 3},
+		{"non-idempotent not retried on 503", 503, true, 1},
+		{"non-idempotent retried on 429", 429, true, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempt := 0
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				attempt++
+				return MockResponse(tc.statusCode, map[string]string{"message": "failure"})
+			})
+			client.retryPolicy = retryPolicy{MaxRetries: 2, MaxDelay: 10 * time.Millisecond}
+
+			req, _ := http.NewRequest(http.MethodPost, "https://api.deepl.com/some-endpoint", strings.NewReader("payload"))
+			if tc.nonIdempotent {
+				req = markNonIdempotent(req)
+			}
+			var er errorResponse
+
+			err := client.doRequest(context.Background(), req, &er)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if attempt != tc.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tc.expectedAttempts, attempt)
+			}
+		})
+	}
+}
+
+func TestEffectiveMaxRetries(t *testing.T) {
+	policy := retryPolicy{MaxRetries: 10, MaxDelay: time.Second, BackoffBase: 20 * time.Millisecond}
+
+	if got := effectiveMaxRetries(context.Background(), policy); got != 10 {
+		t.Errorf("expected MaxRetries without deadline, got %d", got)
+	}
+
+	// Average delays are 10ms, 20ms, 40ms, 80ms, ..., so only three retries fit into 150ms.
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	if got := effectiveMaxRetries(ctx, policy); got != 3 {
+		t.Errorf("expected 3 retries to fit before the deadline, got %d", got)
+	}
+
+	expired, cancelExpired := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelExpired()
+	if got := effectiveMaxRetries(expired, policy); got != 0 {
+		t.Errorf("expected no retries after the deadline, got %d", got)
+	}
+}
+
+func TestSendRequestWithRetry_DeadlineCapsRetries(t *testing.T) {
+	attempt := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		attempt++
+		return MockResponse(503, map[string]string{"message": "service unavailable"})
+	})
+	client.retryPolicy = retryPolicy{MaxRetries: 10, MaxDelay: time.Second, BackoffBase: 20 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.deepl.com/some-endpoint", nil)
+	var er errorResponse
+
+	err := client.doRequest(ctx, req, &er)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 503 {
+		t.Errorf("expected the last 503 response instead of a deadline error, got %v", err)
+	}
+	if attempt > 4 {
+		t.Errorf("expected at most 4 attempts within the deadline, got %d", attempt)
+	}
+}
+
+func TestCalculateRetryDelay_JitterStrategies(t *testing.T) {
+	policy := retryPolicy{MaxRetries: 5, MaxDelay: time.Second, BackoffBase: 100 * time.Millisecond}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		expDelay := exponentialDelay(attempt, policy)
+		for i := 0; i < 100; i++ {
+			policy.Jitter = JitterFull
+			if delay := calculateRetryDelay(attempt, policy); delay < 0 || delay > expDelay {
+				t.Fatalf("full jitter delay %v out of range [0, %v]", delay, expDelay)
+			}
+
+			policy.Jitter = JitterEqual
+			if delay := calculateRetryDelay(attempt, policy); delay < expDelay/2 || delay > expDelay {
+				t.Fatalf("equal jitter delay %v out of range [%v, %v]", delay, expDelay/2, expDelay)
+			}
+		}
+	}
+}
+
+func TestWithRetryPolicy_BackoffBase(t *testing.T) {
+	policy := NewClient("api-key", WithRetryPolicy(3, 10)).retryPolicy
+	if policy.BackoffBase != 500*time.Millisecond {
+		t.Fatalf("expected BackoffBase 500ms, got %v", policy.BackoffBase)
+	}
+
+	var previous time.Duration
+	for attempt := 0; attempt < 4; attempt++ {
+		delay := exponentialDelay(attempt, policy)
+		if delay <= previous {
+			t.Errorf("attempt %d: expected delay to grow beyond %v, got %v", attempt, previous, delay)
+		}
+		previous = delay
+	}
+
+	// The same holds for a client that starts without a policy, such as the test client.
+	client := NewTestClient(nil)
+	WithRetryPolicy(3, 10)(client)
+	if client.retryPolicy.BackoffBase != 500*time.Millisecond {
+		t.Errorf("expected BackoffBase 500ms for a client without a policy, got %v", client.retryPolicy.BackoffBase)
+	}
+}
+
+func TestWithJitterStrategy(t *testing.T) {
+	if NewClient("api-key").retryPolicy.Jitter != JitterFull {
+		t.Error("expected full jitter by default")
+	}
+	if NewClient("api-key", WithJitterStrategy(JitterEqual)).retryPolicy.Jitter != JitterEqual {
+		t.Error("expected equal jitter to be set")
+	}
+	if NewClient("api-key", WithJitterStrategy(JitterEqual), WithRetryPolicy(3, 10)).retryPolicy.Jitter != JitterEqual {
+		t.Error("expected WithRetryPolicy to keep the jitter strategy")
+	}
+}
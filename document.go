@@ -0,0 +1,340 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Document translation statuses reported in DocumentStatus.Status.
+const (
+	DocumentStatusQueued      = "queued"
+	DocumentStatusTranslating = "translating"
+	DocumentStatusDone        = "done"
+	DocumentStatusError       = "error"
+)
+
+// DocumentOptions holds the optional parameters for a document upload.
+type DocumentOptions struct {
+	SourceLang   string    // Source language code, auto-detected if empty
+	Formality    Formality // Formality preference
+	GlossaryID   string    // Glossary ID to apply, requires SourceLang
+	OutputFormat string    // File extension of the desired output format, e.g. "docx"; defaults to the input format
+
+	// FieldNames overrides the names of the multipart form fields of the upload, keyed by DeepL's names:
+	// "file", "target_lang", "source_lang", "formality", "glossary_id", and "output_format". Fields not
+	// listed keep DeepL's names. This helps with gateways that expect different names; the API key is sent
+	// in the Authorization header, see WithAuthScheme.
+	FieldNames map[string]string
+
+	// DocumentPollInterval is the initial wait between status checks in TranslateDocument, which
+	// doubles after every check up to 30 seconds, or up to the interval itself if it is longer.
+	// It defaults to 5 seconds.
+	DocumentPollInterval time.Duration
+}
+
+// documentFieldNames lists DeepL's names of the multipart form fields of a document upload.
+var documentFieldNames = map[string]bool{
+	"file":          true,
+	"target_lang":   true,
+	"source_lang":   true,
+	"formality":     true,
+	"glossary_id":   true,
+	"output_format": true,
+}
+
+// fieldName returns the name of the upload form field DeepL calls name, as overridden by FieldNames.
+func (o *DocumentOptions) fieldName(name string) string {
+	if override, ok := o.FieldNames[name]; ok {
+		return override
+	}
+	return name
+}
+
+// checkFieldNames returns an error if FieldNames overrides an unknown field or sets an empty name.
+func (o *DocumentOptions) checkFieldNames() error {
+	for name, override := range o.FieldNames {
+		if !documentFieldNames[name] {
+			return fmt.Errorf("unknown document upload field %q", name)
+		}
+		if strings.TrimSpace(override) == "" {
+			return fmt.Errorf("name of document upload field %q must not be empty", name)
+		}
+	}
+	return nil
+}
+
+// defaultDocumentPollInterval is the initial wait between status checks unless configured otherwise.
+const defaultDocumentPollInterval = 5 * time.Second
+
+// maxDocumentPollInterval caps the growing wait between status checks of a long-running document translation.
+const maxDocumentPollInterval = 30 * time.Second
+
+// documentFormatConversions lists the output formats DeepL can produce for an input format other than the
+// input format itself, keyed by file extension. It follows the API documentation and must be extended when
+// DeepL adds further conversions.
+var documentFormatConversions = map[string][]string{
+	"doc":  {"docx"},
+	"docx": {"doc", "pdf"},
+	"pdf":  {"docx"},
+}
+
+// checkOutputFormat returns an error if DeepL cannot convert a document named filename into outputFormat.
+func checkOutputFormat(filename, outputFormat string) error {
+	output := strings.ToLower(strings.TrimPrefix(outputFormat, "."))
+	input := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	if output == "" || output == input {
+		return nil
+	}
+	for _, format := range documentFormatConversions[input] {
+		if format == output {
+			return nil
+		}
+	}
+	return fmt.Errorf("conversion of %q to output format %q is not supported", filename, outputFormat)
+}
+
+// DocumentHandle identifies an uploaded document. Both values are required to query its status
+// and download the result, so store them if the translation is resumed later.
+type DocumentHandle struct {
+	DocumentID  string `json:"document_id"`  // Unique ID assigned to the uploaded document
+	DocumentKey string `json:"document_key"` // Encryption key of the uploaded document
+}
+
+// DocumentStatus describes the progress of a document translation.
+type DocumentStatus struct {
+	DocumentID       string `json:"document_id"`       // ID of the document
+	Status           string `json:"status"`            // One of the DocumentStatus constants
+	SecondsRemaining int    `json:"seconds_remaining"` // Estimated seconds until the translation is done, if translating
+	BilledCharacters int    `json:"billed_characters"` // Characters billed for the translation, once done
+	ErrorMessage     string `json:"error_message"`     // Description of the failure, if the status is "error"
+	Message          string `json:"message"`           // Alternative description of the failure sent by some servers
+	ErrorCode        int    `json:"error_code"`        // Code of the failure, if reported
+}
+
+// UploadDocument uploads the document read from r for translation into targetLang.
+// It uses a background context; see UploadDocumentWithContext for details.
+func (c *Client) UploadDocument(r io.Reader, filename, targetLang string, opts *DocumentOptions) (*DocumentHandle, error) {
+	return c.UploadDocumentWithContext(context.Background(), r, filename, targetLang, opts)
+}
+
+// UploadDocumentWithContext uploads the document read from r for translation into targetLang and returns the handle
+// of the uploaded document. The filename is sent to DeepL to determine the document format. opts may be nil.
+// If opts.OutputFormat asks for a conversion DeepL does not offer for that format, or opts.FieldNames overrides
+// an unknown field, no request is sent.
+// As a repeated upload would translate and bill the document twice, it is only retried on 429.
+func (c *Client) UploadDocumentWithContext(ctx context.Context, r io.Reader, filename, targetLang string, opts *DocumentOptions) (*DocumentHandle, error) {
+	if opts == nil {
+		opts = &DocumentOptions{}
+	}
+	if opts.Formality != FormalityUnset && opts.Formality.String() == "" {
+		return nil, fmt.Errorf("invalid formality value %d", opts.Formality)
+	}
+	if err := checkOutputFormat(filename, opts.OutputFormat); err != nil {
+		return nil, err
+	}
+	if err := opts.checkFieldNames(); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fields := []struct{ name, value string }{
+		{"target_lang", targetLang},
+		{"source_lang", opts.SourceLang},
+		{"formality", opts.Formality.String()},
+		{"glossary_id", opts.GlossaryID},
+		{"output_format", opts.OutputFormat},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		if err := mw.WriteField(opts.fieldName(field.name), field.value); err != nil {
+			return nil, err
+		}
+	}
+	part, err := mw.CreateFormFile(opts.fieldName("file"), filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("%s/v2/document", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return doJSON[DocumentHandle](c, ctx, markNonIdempotent(req))
+}
+
+// GetDocumentStatus retrieves the translation status of the document identified by handle.
+// It uses a background context; see GetDocumentStatusWithContext for details.
+func (c *Client) GetDocumentStatus(handle *DocumentHandle) (*DocumentStatus, error) {
+	return c.GetDocumentStatusWithContext(context.Background(), handle)
+}
+
+// GetDocumentStatusWithContext retrieves the translation status of the document identified by handle.
+func (c *Client) GetDocumentStatusWithContext(ctx context.Context, handle *DocumentHandle) (*DocumentStatus, error) {
+	req, err := c.newDocumentRequest(ctx, handle, "")
+	if err != nil {
+		return nil, err
+	}
+	return doJSON[DocumentStatus](c, ctx, req)
+}
+
+// DownloadDocument writes the translated document identified by handle to w.
+// It uses a background context; see DownloadDocumentWithContext for details.
+func (c *Client) DownloadDocument(handle *DocumentHandle, w io.Writer) error {
+	return c.DownloadDocumentWithContext(context.Background(), handle, w)
+}
+
+// DownloadDocumentWithContext writes the translated document identified by handle to w.
+// The translation must be done. The handle need not come from an upload by this client: a handle persisted
+// after the upload can be used to download the result later, e.g. by another process, for as long as DeepL
+// keeps the document. If DeepL reports the document as gone (404 Not Found or 410 Gone), the returned error
+// wraps ErrDocumentExpired.
+func (c *Client) DownloadDocumentWithContext(ctx context.Context, handle *DocumentHandle, w io.Writer) error {
+	req, err := c.newDocumentRequest(ctx, handle, "/result")
+	if err != nil {
+		return err
+	}
+	err = c.doRequestRaw(ctx, req, func(body io.Reader) error {
+		if _, err := io.Copy(w, body); err != nil {
+			return fmt.Errorf("failed to write document: %w", err)
+		}
+		return nil
+	})
+	if hasStatusCode(err, http.StatusNotFound) || hasStatusCode(err, http.StatusGone) {
+		return fmt.Errorf("%w: %w", ErrDocumentExpired, err)
+	}
+	return err
+}
+
+// newDocumentRequest builds a request to the endpoint of the document identified by handle, authenticated
+// with the document key. The suffix is appended to the document's path, e.g. "/result".
+func (c *Client) newDocumentRequest(ctx context.Context, handle *DocumentHandle, suffix string) (*http.Request, error) {
+	if handle == nil || handle.DocumentID == "" {
+		return nil, errors.New("document handle with a document ID is required")
+	}
+	data, err := json.Marshal(map[string]string{"document_key": handle.DocumentKey})
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/v2/document/%s%s", c.baseURL, url.PathEscape(handle.DocumentID), suffix)
+	return http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+}
+
+// TranslateDocument translates the document read from in into targetLang and writes the result to out.
+// It uses a background context; see TranslateDocumentWithContext for details.
+func (c *Client) TranslateDocument(in io.Reader, filename, targetLang string, out io.Writer, opts *DocumentOptions) error {
+	return c.TranslateDocumentWithContext(context.Background(), in, filename, targetLang, out, opts)
+}
+
+// TranslateDocumentWithContext uploads the document read from in, waits for its translation into targetLang, and writes
+// the translated document to out. The status is checked after opts.DocumentPollInterval, with the wait
+// doubling after every check. If DeepL reports a failure, a *DocumentError is returned. opts may be nil.
+// Cancelling ctx stops waiting; the uploaded document is then left on the server until it expires.
+func (c *Client) TranslateDocumentWithContext(ctx context.Context, in io.Reader, filename, targetLang string, out io.Writer, opts *DocumentOptions) error {
+	return c.translateDocument(ctx, in, filename, targetLang, out, opts, func(*DocumentStatus) {})
+}
+
+// DocumentProgress is a status update emitted by TranslateDocumentWithProgress.
+type DocumentProgress struct {
+	Status           string // One of the DocumentStatus constants
+	SecondsRemaining int    // Estimated seconds until the translation is done, if translating
+}
+
+// documentProgressBuffer is the number of progress updates buffered for a caller that does not keep up.
+const documentProgressBuffer = 16
+
+// TranslateDocumentWithProgress translates a document like TranslateDocument in the background and emits
+// a DocumentProgress for every status check. The error channel receives exactly one value, nil on success,
+// once the translated document was written to out. Both channels are closed afterwards.
+// Progress updates are buffered; if the caller stops reading them, further updates are dropped rather
+// than blocking the translation, so no goroutine is left behind.
+func (c *Client) TranslateDocumentWithProgress(ctx context.Context, in io.Reader, filename, targetLang string, out io.Writer, opts *DocumentOptions) (<-chan DocumentProgress, <-chan error) {
+	progress := make(chan DocumentProgress, documentProgressBuffer)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(progress)
+		errs <- c.translateDocument(ctx, in, filename, targetLang, out, opts, func(status *DocumentStatus) {
+			select {
+			case progress <- DocumentProgress{Status: status.Status, SecondsRemaining: status.SecondsRemaining}:
+			default:
+			}
+		})
+	}()
+	return progress, errs
+}
+
+// translateDocument implements TranslateDocument, calling onStatus with every status received while polling.
+func (c *Client) translateDocument(ctx context.Context, in io.Reader, filename, targetLang string, out io.Writer, opts *DocumentOptions, onStatus func(*DocumentStatus)) error {
+	handle, err := c.UploadDocumentWithContext(ctx, in, filename, targetLang, opts)
+	if err != nil {
+		return err
+	}
+
+	interval := defaultDocumentPollInterval
+	if opts != nil && opts.DocumentPollInterval > 0 {
+		interval = opts.DocumentPollInterval
+	}
+	initial := interval
+
+	for {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		status, err := c.GetDocumentStatusWithContext(ctx, handle)
+		if err != nil {
+			return err
+		}
+		onStatus(status)
+		switch status.Status {
+		case DocumentStatusDone:
+			return c.DownloadDocumentWithContext(ctx, handle, out)
+		case DocumentStatusError:
+			message := status.ErrorMessage
+			if message == "" {
+				message = status.Message
+			}
+			return &DocumentError{DocumentID: handle.DocumentID, Message: message, ErrorCode: status.ErrorCode}
+		}
+
+		interval = nextDocumentPollInterval(interval, initial)
+	}
+}
+
+// nextDocumentPollInterval returns the wait before the next status check: interval doubled, capped at
+// maxDocumentPollInterval. The cap only limits the backoff, so an initial interval above it is kept.
+func nextDocumentPollInterval(interval, initial time.Duration) time.Duration {
+	maxInterval := maxDocumentPollInterval
+	if initial > maxInterval {
+		maxInterval = initial
+	}
+	interval *= 2
+	if interval > maxInterval {
+		return maxInterval
+	}
+	return interval
+}
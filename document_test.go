@@ -0,0 +1,599 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadDocument(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.Method != http.MethodPost || req.URL.Path != "/v2/document" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+
+		mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+			t.Fatalf("expected multipart/form-data with boundary, got %q", req.Header.Get("Content-Type"))
+		}
+
+		fields := make(map[string]string)
+		mr := multipart.NewReader(req.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("failed to read multipart body: %v", err)
+			}
+			value, _ := io.ReadAll(part)
+			if part.FormName() == "file" && part.FileName() != "report.txt" {
+				t.Errorf("expected filename 'report.txt', got %q", part.FileName())
+			}
+			fields[part.FormName()] = string(value)
+		}
+
+		expected := map[string]string{
+			"file":        "Hello world",
+			"target_lang": "DE",
+			"source_lang": "EN",
+			"formality":   "more",
+		}
+		for name, value := range expected {
+			if fields[name] != value {
+				t.Errorf("expected field %s=%q, got %q", name, value, fields[name])
+			}
+		}
+		if _, ok := fields["glossary_id"]; ok {
+			t.Error("expected empty glossary_id to be omitted")
+		}
+
+		return MockResponse(200, DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+	})
+
+	handle, err := client.UploadDocumentWithContext(context.Background(), strings.NewReader("Hello world"), "report.txt", "DE",
+		&DocumentOptions{SourceLang: "EN", Formality: FormalityMore})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handle.DocumentID != "doc-1" || handle.DocumentKey != "key-1" {
+		t.Errorf("unexpected handle: %+v", handle)
+	}
+}
+
+// readMultipartFields returns the values of the multipart form fields of req keyed by name, with the
+// file name of file parts appended after a colon.
+func readMultipartFields(t *testing.T, req *http.Request) map[string]string {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("invalid Content-Type %q: %v", req.Header.Get("Content-Type"), err)
+	}
+
+	fields := make(map[string]string)
+	mr := multipart.NewReader(req.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return fields
+		}
+		if err != nil {
+			t.Fatalf("failed to read multipart body: %v", err)
+		}
+		value, _ := io.ReadAll(part)
+		if part.FileName() != "" {
+			value = append(value, ":"+part.FileName()...)
+		}
+		fields[part.FormName()] = string(value)
+	}
+}
+
+func TestUploadDocument_FieldNames(t *testing.T) {
+	allOptions := DocumentOptions{SourceLang: "EN", Formality: FormalityLess, GlossaryID: "g1", OutputFormat: "pdf"}
+	overridden := allOptions
+	overridden.FieldNames = map[string]string{"file": "document", "target_lang": "to", "source_lang": "from"}
+
+	testCases := []struct {
+		name     string
+		opts     DocumentOptions
+		expected map[string]string
+	}{
+		{"default", allOptions, map[string]string{
+			"file":          "Hello world:report.docx",
+			"target_lang":   "DE",
+			"source_lang":   "EN",
+			"formality":     "less",
+			"glossary_id":   "g1",
+			"output_format": "pdf",
+		}},
+		{"overridden", overridden, map[string]string{
+			"document":      "Hello world:report.docx",
+			"to":            "DE",
+			"from":          "EN",
+			"formality":     "less",
+			"glossary_id":   "g1",
+			"output_format": "pdf",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				if fields := readMultipartFields(t, req); !reflect.DeepEqual(fields, tc.expected) {
+					t.Errorf("expected fields %v, got %v", tc.expected, fields)
+				}
+				return MockResponse(200, DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+			})
+
+			opts := tc.opts
+			if _, err := client.UploadDocumentWithContext(context.Background(), strings.NewReader("Hello world"), "report.docx", "DE", &opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestUploadDocument_InvalidFieldNames(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Error("should not send a request with invalid field names")
+		return nil
+	})
+
+	for _, names := range []map[string]string{{"auth_key": "key"}, {"file": " "}} {
+		_, err := client.UploadDocumentWithContext(context.Background(), strings.NewReader("Hello world"), "report.txt", "DE",
+			&DocumentOptions{FieldNames: names})
+		if err == nil {
+			t.Errorf("expected error for field names %v", names)
+		}
+	}
+}
+
+func TestUploadDocument_NotRetriedOnServerError(t *testing.T) {
+	attempts := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		attempts++
+		return MockResponse(503, map[string]string{"message": "service unavailable"})
+	})
+	client.retryPolicy = retryPolicy{MaxRetries: 2, MaxDelay: 10 * time.Millisecond}
+
+	_, err := client.UploadDocumentWithContext(context.Background(), strings.NewReader("Hello"), "a.txt", "DE", nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if attempts != 1 {
+		t.Errorf("expected the upload not to be repeated, got %d attempts", attempts)
+	}
+}
+
+func TestDocumentPollingAndDownload_RetriedOnServerError(t *testing.T) {
+	handle := &DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"}
+	testCases := []struct {
+		name string
+		call func(c *Client) error
+		ok   *http.Response
+	}{
+		{"status", func(c *Client) error {
+			_, err := c.GetDocumentStatusWithContext(context.Background(), handle)
+			return err
+		}, MockResponse(200, DocumentStatus{DocumentID: "doc-1", Status: DocumentStatusTranslating})},
+		{"download", func(c *Client) error {
+			return c.DownloadDocumentWithContext(context.Background(), handle, io.Discard)
+		}, &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("Hallo Welt")), Header: make(http.Header)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				attempts++
+				if attempts == 1 {
+					return MockResponse(503, map[string]string{"message": "service unavailable"})
+				}
+				return tc.ok
+			})
+			WithRetryPolicy(1, 1)(client)
+			client.retryPolicy.BackoffBase = time.Millisecond
+
+			if err := tc.call(client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if attempts != 2 {
+				t.Errorf("expected the request to be retried once, got %d attempts", attempts)
+			}
+		})
+	}
+}
+
+func TestUploadDocument_OutputFormat(t *testing.T) {
+	var outputFormat string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("failed to parse multipart body: %v", err)
+		}
+		outputFormat = req.FormValue("output_format")
+		return MockResponse(200, DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+	})
+
+	_, err := client.UploadDocument(strings.NewReader("%PDF"), "report.PDF", "DE", &DocumentOptions{OutputFormat: "docx"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outputFormat != "docx" {
+		t.Errorf("expected output_format 'docx', got %q", outputFormat)
+	}
+}
+
+func TestUploadDocument_UnsupportedOutputFormat(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Error("should not send a request for an unsupported conversion")
+		return nil
+	})
+
+	_, err := client.UploadDocument(strings.NewReader("<p>Hello</p>"), "page.html", "DE", &DocumentOptions{OutputFormat: "pdf"})
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected unsupported conversion error, got %v", err)
+	}
+}
+
+func TestCheckOutputFormat(t *testing.T) {
+	testCases := []struct {
+		filename     string
+		outputFormat string
+		valid        bool
+	}{
+		{"report.docx", "", true},
+		{"report.docx", "docx", true},
+		{"report.docx", "pdf", true},
+		{"scan.pdf", ".DOCX", true},
+		{"notes.txt", "txt", true},
+		{"notes.txt", "pdf", false},
+		{"slides.pptx", "docx", false},
+		{"report", "pdf", false},
+	}
+
+	for _, tc := range testCases {
+		err := checkOutputFormat(tc.filename, tc.outputFormat)
+		if (err == nil) != tc.valid {
+			t.Errorf("checkOutputFormat(%q, %q): expected valid %v, got %v", tc.filename, tc.outputFormat, tc.valid, err)
+		}
+	}
+}
+
+func TestGetDocumentStatus(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path != "/v2/document/doc-1" {
+			t.Errorf("unexpected path: %s", req.URL.Path)
+		}
+		var body map[string]string
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		if body["document_key"] != "key-1" {
+			t.Errorf("expected document_key 'key-1', got %q", body["document_key"])
+		}
+		return MockResponse(200, DocumentStatus{
+			DocumentID:       "doc-1",
+			Status:           DocumentStatusTranslating,
+			SecondsRemaining: 20,
+		})
+	})
+
+	status, err := client.GetDocumentStatusWithContext(context.Background(), &DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Status != DocumentStatusTranslating || status.SecondsRemaining != 20 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestDownloadDocument(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path != "/v2/document/doc-1/result" {
+			t.Errorf("unexpected path: %s", req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("Hallo Welt")),
+			Header:     http.Header{"Content-Type": {"application/octet-stream"}},
+		}
+	})
+
+	var out bytes.Buffer
+	err := client.DownloadDocumentWithContext(context.Background(), &DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"}, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "Hallo Welt" {
+		t.Errorf("expected downloaded content 'Hallo Welt', got %q", out.String())
+	}
+}
+
+func TestDownloadDocument_PersistedHandle(t *testing.T) {
+	// A handle persisted after the upload, e.g. by another process, is enough to download the result.
+	data, err := json.Marshal(&DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var handle DocumentHandle
+	if err := json.Unmarshal(data, &handle); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path != "/v2/document/doc-1/result" {
+			t.Errorf("unexpected path: %s", req.URL.Path)
+		}
+		body, _ := io.ReadAll(req.Body)
+		if !strings.Contains(string(body), `"document_key":"key-1"`) {
+			t.Errorf("expected the document key in the request body, got %s", body)
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("Hallo Welt")),
+			Header:     http.Header{"Content-Type": {"application/octet-stream"}},
+		}
+	})
+
+	var out bytes.Buffer
+	if err := client.DownloadDocumentWithContext(context.Background(), &handle, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "Hallo Welt" {
+		t.Errorf("expected downloaded content 'Hallo Welt', got %q", out.String())
+	}
+}
+
+func TestDownloadDocument_Expired(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusGone} {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			return MockResponse(status, map[string]string{"message": "Document not found"})
+		})
+
+		var out bytes.Buffer
+		err := client.DownloadDocumentWithContext(context.Background(), &DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"}, &out)
+		if !errors.Is(err, ErrDocumentExpired) {
+			t.Errorf("status %d: expected ErrDocumentExpired, got %v", status, err)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+			t.Errorf("status %d: expected the API error to be wrapped, got %v", status, err)
+		}
+	}
+}
+
+func TestDocumentRequests_MissingHandle(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Error("should not send a request without a document ID")
+		return nil
+	})
+
+	if _, err := client.GetDocumentStatusWithContext(context.Background(), nil); err == nil {
+		t.Error("expected error for nil handle")
+	}
+	if err := client.DownloadDocumentWithContext(context.Background(), &DocumentHandle{}, io.Discard); err == nil {
+		t.Error("expected error for empty document ID")
+	}
+}
+
+func TestTranslateDocument(t *testing.T) {
+	var paths []string
+	polls := 0
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		paths = append(paths, req.URL.Path)
+		switch req.URL.Path {
+		case "/v2/document":
+			return MockResponse(200, DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+		case "/v2/document/doc-1":
+			polls++
+			if polls == 1 {
+				return MockResponse(200, DocumentStatus{DocumentID: "doc-1", Status: DocumentStatusTranslating, SecondsRemaining: 1})
+			}
+			return MockResponse(200, DocumentStatus{DocumentID: "doc-1", Status: DocumentStatusDone, BilledCharacters: 11})
+		case "/v2/document/doc-1/result":
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("Hallo Welt")), Header: make(http.Header)}
+		}
+		t.Errorf("unexpected path: %s", req.URL.Path)
+		return MockResponse(404, nil)
+	})
+
+	var out bytes.Buffer
+	err := client.TranslateDocumentWithContext(context.Background(), strings.NewReader("Hello world"), "a.txt", "DE", &out,
+		&DocumentOptions{DocumentPollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "Hallo Welt" {
+		t.Errorf("expected 'Hallo Welt', got %q", out.String())
+	}
+	if polls != 2 {
+		t.Errorf("expected 2 status polls, got %d", polls)
+	}
+	expected := "/v2/document,/v2/document/doc-1,/v2/document/doc-1,/v2/document/doc-1/result"
+	if strings.Join(paths, ",") != expected {
+		t.Errorf("expected requests %s, got %v", expected, paths)
+	}
+}
+
+func TestTranslateDocument_Error(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/v2/document" {
+			return MockResponse(200, DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+		}
+		return MockResponse(200, map[string]any{
+			"document_id": "doc-1",
+			"status":      "error",
+			"message":     "Source and target language are equal",
+			"error_code":  2,
+		})
+	})
+
+	err := client.TranslateDocumentWithContext(context.Background(), strings.NewReader("Hello"), "a.txt", "EN-US", io.Discard,
+		&DocumentOptions{DocumentPollInterval: time.Millisecond})
+
+	var docErr *DocumentError
+	if !errors.As(err, &docErr) {
+		t.Fatalf("expected *DocumentError, got %v", err)
+	}
+	if docErr.Message != "Source and target language are equal" || docErr.ErrorCode != 2 {
+		t.Errorf("unexpected document error: %+v", docErr)
+	}
+	if err.Error() != "translation of document doc-1 failed (error code 2): Source and target language are equal" {
+		t.Errorf("unexpected error message: %s", err)
+	}
+}
+
+func TestTranslateDocument_ContextCancel(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/v2/document" {
+			return MockResponse(200, DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+		}
+		return MockResponse(200, DocumentStatus{DocumentID: "doc-1", Status: DocumentStatusTranslating})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := client.TranslateDocumentWithContext(ctx, strings.NewReader("Hello"), "a.txt", "DE", io.Discard,
+		&DocumentOptions{DocumentPollInterval: 10 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestNextDocumentPollInterval(t *testing.T) {
+	testCases := []struct {
+		name     string
+		interval time.Duration
+		initial  time.Duration
+		expected time.Duration
+	}{
+		{"doubles", 5 * time.Second, 5 * time.Second, 10 * time.Second},
+		{"capped", 20 * time.Second, 5 * time.Second, maxDocumentPollInterval},
+		{"initial interval above the cap is kept", time.Minute, time.Minute, time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := nextDocumentPollInterval(tc.interval, tc.initial); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTranslateDocumentWithProgress(t *testing.T) {
+	polls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/v2/document":
+			return MockResponse(200, DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+		case "/v2/document/doc-1":
+			polls++
+			switch polls {
+			case 1:
+				return MockResponse(200, DocumentStatus{Status: DocumentStatusQueued})
+			case 2:
+				return MockResponse(200, DocumentStatus{Status: DocumentStatusTranslating, SecondsRemaining: 3})
+			}
+			return MockResponse(200, DocumentStatus{Status: DocumentStatusDone})
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("Hallo")), Header: make(http.Header)}
+	})
+
+	var out bytes.Buffer
+	progress, errs := client.TranslateDocumentWithProgress(context.Background(), strings.NewReader("Hello"), "a.txt", "DE", &out,
+		&DocumentOptions{DocumentPollInterval: time.Millisecond})
+
+	var events []DocumentProgress
+	for p := range progress {
+		events = append(events, p)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := <-errs; ok {
+		t.Error("expected error channel to be closed after the result")
+	}
+
+	expected := []DocumentProgress{
+		{Status: DocumentStatusQueued},
+		{Status: DocumentStatusTranslating, SecondsRemaining: 3},
+		{Status: DocumentStatusDone},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected progress %+v, got %+v", expected, events)
+	}
+	if out.String() != "Hallo" {
+		t.Errorf("expected 'Hallo', got %q", out.String())
+	}
+}
+
+func TestTranslateDocumentWithProgress_CallerStopsReading(t *testing.T) {
+	polls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/v2/document" {
+			return MockResponse(200, DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"})
+		}
+		polls++
+		if polls <= documentProgressBuffer+2 {
+			return MockResponse(200, DocumentStatus{Status: DocumentStatusTranslating})
+		}
+		return MockResponse(200, DocumentStatus{Status: DocumentStatusError, ErrorMessage: "failed"})
+	})
+
+	_, errs := client.TranslateDocumentWithProgress(context.Background(), strings.NewReader("Hello"), "a.txt", "DE", io.Discard,
+		&DocumentOptions{DocumentPollInterval: time.Microsecond})
+
+	// Progress is never read; the translation must still finish instead of blocking on a full channel.
+	select {
+	case err := <-errs:
+		var docErr *DocumentError
+		if !errors.As(err, &docErr) {
+			t.Errorf("expected *DocumentError, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("translation blocked on unread progress updates")
+	}
+}
+
+func TestDocumentMethods_ContextCanceled(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	handle := &DocumentHandle{DocumentID: "doc-1", DocumentKey: "key-1"}
+
+	calls := map[string]func() error{
+		"UploadDocumentWithContext": func() error {
+			_, err := client.UploadDocumentWithContext(ctx, strings.NewReader("Hello"), "a.txt", "DE", nil)
+			return err
+		},
+		"GetDocumentStatusWithContext": func() error {
+			_, err := client.GetDocumentStatusWithContext(ctx, handle)
+			return err
+		},
+		"DownloadDocumentWithContext": func() error {
+			return client.DownloadDocumentWithContext(ctx, handle, io.Discard)
+		},
+		"TranslateDocumentWithContext": func() error {
+			return client.TranslateDocumentWithContext(ctx, strings.NewReader("Hello"), "a.txt", "DE", io.Discard, nil)
+		},
+	}
+
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package deepl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrAuthFailed is returned by credential checks such as Authenticate when DeepL rejects the API key.
+// The returned error also wraps the underlying *APIError.
+var ErrAuthFailed = errors.New("authentication failed")
+
+// ErrDocumentExpired is returned when downloading a document that DeepL no longer keeps, e.g. because its
+// retention period has passed. The returned error also wraps the underlying *APIError.
+var ErrDocumentExpired = errors.New("document expired")
+
+// ErrSourceLangRequired is returned without sending a request when a translation relies on source language
+// auto-detection although the client was created with WithRequiredSourceLang.
+var ErrSourceLangRequired = errors.New("source language is required when auto-detection is disabled")
+
+// ErrEmptyResult is returned when DeepL answers successfully but without any result, e.g. an empty list of
+// translations or languages. The returned error names the missing result.
+var ErrEmptyResult = errors.New("empty result")
+
+// APIError is returned when the DeepL API responds with a non-success HTTP status code, including
+// when the retries for a retryable status such as 429 or 503 are exhausted.
+// Use errors.As to extract it from an error returned by the client. Decoding failures, network errors,
+// and context cancellations carry no HTTP status and are therefore never an APIError.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response
+	Status     string // Status line of the response as sent by the server, e.g. "503 Service Unavailable"
+	Message    string // Error message returned by DeepL, empty if the body had none
+	Code       string // Error code returned by DeepL, empty if the body had none
+	TraceID    string // Value of the X-Trace-ID response header, to be quoted in support requests to DeepL
+
+	statusText string // Lowercase description of the status code used in Error
+	bodyErr    error  // Error encountered while reading the response body, if any
+}
+
+// Error returns a description of the error in the form "HTTP <code> <status>: <message>".
+func (e *APIError) Error() string {
+	if e.bodyErr != nil {
+		return fmt.Sprintf("HTTP %d %s; error reading the body: %v", e.StatusCode, e.statusText, e.bodyErr)
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("HTTP %d %s: %s", e.StatusCode, e.statusText, e.Message)
+	}
+	return fmt.Sprintf("HTTP %d %s", e.StatusCode, e.statusText)
+}
+
+// Unwrap returns the error encountered while reading the response body, if any.
+func (e *APIError) Unwrap() error {
+	return e.bodyErr
+}
+
+// IsQuotaExceeded reports whether err is an *APIError for status 456, which DeepL returns once the
+// character limit of the billing period is reached.
+func IsQuotaExceeded(err error) bool {
+	return hasStatusCode(err, 456)
+}
+
+// IsRateLimited reports whether err is an *APIError for status 429, which DeepL returns for too many
+// requests. The client has already retried the request according to its retry policy.
+func IsRateLimited(err error) bool {
+	return hasStatusCode(err, http.StatusTooManyRequests)
+}
+
+// IsAuthError reports whether err is due to DeepL rejecting the API key, i.e. it wraps ErrAuthFailed or is an
+// *APIError for status 403.
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrAuthFailed) || hasStatusCode(err, http.StatusForbidden)
+}
+
+// hasStatusCode reports whether err is an *APIError with the given HTTP status code.
+func hasStatusCode(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// DocumentError is returned by TranslateDocument when DeepL reports that a document could not be translated.
+type DocumentError struct {
+	DocumentID string // ID of the failed document
+	Message    string // Description of the failure reported by DeepL, empty if none was given
+	ErrorCode  int    // Code of the failure reported by DeepL, zero if none was given
+}
+
+// Error returns a description of the failure including the error code and message, if reported.
+func (e *DocumentError) Error() string {
+	msg := fmt.Sprintf("translation of document %s failed", e.DocumentID)
+	if e.ErrorCode != 0 {
+		msg += fmt.Sprintf(" (error code %d)", e.ErrorCode)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// checkNotEmpty returns an error wrapping ErrEmptyResult if items is empty, naming the missing result as what,
+// e.g. "no translation returned". Endpoints for which an empty result is never valid use it on their response.
+func checkNotEmpty[T any](items []T, what string) error {
+	if len(items) == 0 {
+		return fmt.Errorf("%w: no %s returned", ErrEmptyResult, what)
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIError_StatusExtraction(t *testing.T) {
+	testCases := []struct {
+		name           string
+		statusCode     int
+		body           string
+		maxRetries     int
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{"client error", 403, `{"message":"Wrong API key"}`, 0, 403, "Wrong API key"},
+		{"quota exceeded", 456, "", 0, 456, ""},
+		{"retries exhausted", 503, `{"message":"Try again later"}`, 2, 503, "Try again later"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: tc.statusCode,
+					Body:       io.NopCloser(strings.NewReader(tc.body)),
+					Header:     make(http.Header),
+				}
+			})
+			client.retryPolicy = retryPolicy{MaxRetries: tc.maxRetries, MaxDelay: time.Millisecond}
+
+			_, err := client.GetUsage()
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got %T: %v", err, err)
+			}
+			if apiErr.StatusCode != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d", tc.expectedStatus, apiErr.StatusCode)
+			}
+			if apiErr.Message != tc.expectedMsg {
+				t.Errorf("expected message %q, got %q", tc.expectedMsg, apiErr.Message)
+			}
+		})
+	}
+}
+
+func TestAPIError_NoStatusForDecodeAndNetworkErrors(t *testing.T) {
+	t.Run("decode error", func(t *testing.T) {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader("invalid json")),
+				Header:     make(http.Header),
+			}
+		})
+
+		_, err := client.GetUsage()
+		if err == nil {
+			t.Fatal("expected decode error, got nil")
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			t.Errorf("expected no *APIError for decode failure, got %v", apiErr)
+		}
+	})
+
+	t.Run("network error", func(t *testing.T) {
+		client := NewTestClient(nil)
+		client.httpClient.Transport = RoundTripErrFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})
+
+		_, err := client.GetUsage()
+		if err == nil {
+			t.Fatal("expected network error, got nil")
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			t.Errorf("expected no *APIError for network failure, got %v", apiErr)
+		}
+	})
+}
+
+func TestAPIError_Error(t *testing.T) {
+	testCases := []struct {
+		err      *APIError
+		expected string
+	}{
+		{&APIError{StatusCode: 400, Message: "Value for 'target_lang' not supported.", statusText: "bad request"}, "HTTP 400 bad request: Value for 'target_lang' not supported."},
+		{&APIError{StatusCode: 456, statusText: "character limit has been reached"}, "HTTP 456 character limit has been reached"},
+		{&APIError{StatusCode: 500, statusText: "internal server error", bodyErr: io.ErrUnexpectedEOF}, "HTTP 500 internal server error; error reading the body: unexpected EOF"},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.err.Error(); got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, got)
+		}
+	}
+
+	if !errors.Is(testCases[2].err, io.ErrUnexpectedEOF) {
+		t.Error("expected body read error to be unwrapped")
+	}
+}
+
+func TestAPIError_ContextCancellation(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(503, nil)
+	})
+	client.retryPolicy = retryPolicy{MaxRetries: 3, MaxDelay: time.Second, BackoffBase: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := client.GetUsageWithContext(ctx)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("expected no *APIError for cancelled request, got %v", apiErr)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAPIError_Code(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"string code", `{"message":"Quota exceeded","code":"quota_exceeded"}`, "quota_exceeded"},
+		{"numeric code", `{"message":"Quota exceeded","code":1042}`, "1042"},
+		{"no code", `{"message":"Quota exceeded"}`, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				return &http.Response{StatusCode: 456, Body: io.NopCloser(strings.NewReader(tc.body)), Header: make(http.Header)}
+			})
+
+			_, err := client.GetUsage()
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got %v", err)
+			}
+			if apiErr.Code != tc.expected {
+				t.Errorf("expected code %q, got %q", tc.expected, apiErr.Code)
+			}
+			if err.Error() != "HTTP 456 character limit has been reached: Quota exceeded" {
+				t.Errorf("unexpected error message: %s", err)
+			}
+		})
+	}
+}
+
+func TestAPIError_TraceID(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		header := make(http.Header)
+		header.Set("X-Trace-ID", "a1b2c3d4")
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader(`{"message":"Value for 'target_lang' not supported."}`)),
+			Header:     header,
+		}
+	})
+
+	_, err := client.TranslateText("Hello", "XX")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.TraceID != "a1b2c3d4" {
+		t.Errorf("expected trace ID 'a1b2c3d4', got %q", apiErr.TraceID)
+	}
+}
+
+func TestAPIError_Status(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusBadGateway,
+			Status:     "502 Upstream Gateway Unreachable",
+			Body:       io.NopCloser(strings.NewReader("")),
+			Header:     make(http.Header),
+		}
+	})
+
+	_, err := client.GetUsage()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.Status != "502 Upstream Gateway Unreachable" {
+		t.Errorf("expected the original status line, got %q", apiErr.Status)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected status code 502, got %d", apiErr.StatusCode)
+	}
+}
+
+func TestIsQuotaExceededAndIsRateLimited(t *testing.T) {
+	testCases := []struct {
+		err         error
+		quota, rate bool
+	}{
+		{&APIError{StatusCode: 456}, true, false},
+		{fmt.Errorf("translate: %w", &APIError{StatusCode: 456}), true, false},
+		{&APIError{StatusCode: 429}, false, true},
+		{&APIError{StatusCode: 403}, false, false},
+		{errors.New("HTTP 456"), false, false},
+		{nil, false, false},
+	}
+
+	for _, tc := range testCases {
+		if got := IsQuotaExceeded(tc.err); got != tc.quota {
+			t.Errorf("IsQuotaExceeded(%v): expected %v, got %v", tc.err, tc.quota, got)
+		}
+		if got := IsRateLimited(tc.err); got != tc.rate {
+			t.Errorf("IsRateLimited(%v): expected %v, got %v", tc.err, tc.rate, got)
+		}
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected bool
+	}{
+		{&APIError{StatusCode: 403}, true},
+		{fmt.Errorf("%w: %w", ErrAuthFailed, &APIError{StatusCode: 403}), true},
+		{ErrAuthFailed, true},
+		{&APIError{StatusCode: 456}, false},
+		{errors.New("HTTP 403"), false},
+		{nil, false},
+	}
+
+	for _, tc := range testCases {
+		if got := IsAuthError(tc.err); got != tc.expected {
+			t.Errorf("IsAuthError(%v): expected %v, got %v", tc.err, tc.expected, got)
+		}
+	}
+}
+
+func TestErrEmptyResult(t *testing.T) {
+	testCases := []struct {
+		name string
+		body any
+		call func(c *Client) error
+	}{
+		{"translate", TranslationsResponse{Translations: []*Translation{}}, func(c *Client) error {
+			_, err := c.TranslateText("Hello", "DE")
+			return err
+		}},
+		{"translate with options", TranslationsResponse{}, func(c *Client) error {
+			_, err := c.TranslateTextWithOptions(context.Background(), TranslateTextOptions{Text: []string{"Hello"}, TargetLang: "DE"})
+			return err
+		}},
+		{"source languages", []*Language{}, func(c *Client) error {
+			_, err := c.GetSourceLanguages()
+			return err
+		}},
+		{"target languages", []*Language{}, func(c *Client) error {
+			_, err := c.GetTargetLanguages()
+			return err
+		}},
+		{"rephrase", RephraseResponse{Improvements: []*Improvement{}}, func(c *Client) error {
+			_, err := c.Rephrase("Hello")
+			return err
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				return MockResponse(200, tc.body)
+			})
+
+			err := tc.call(client)
+			if !errors.Is(err, ErrEmptyResult) {
+				t.Errorf("expected ErrEmptyResult, got %v", err)
+			}
+		})
+	}
+}
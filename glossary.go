@@ -0,0 +1,447 @@
+package deepl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Glossary describes a glossary stored in the DeepL account.
+type Glossary struct {
+	GlossaryID   string    `json:"glossary_id"`   // Unique ID of the glossary, e.g. for TranslateTextOptions.GlossaryID
+	Name         string    `json:"name"`          // Name of the glossary
+	Ready        bool      `json:"ready"`         // Indicates if the glossary can already be used in translations
+	SourceLang   string    `json:"source_lang"`   // Language code of the source terms
+	TargetLang   string    `json:"target_lang"`   // Language code of the target terms
+	CreationTime time.Time `json:"creation_time"` // Time the glossary was created
+	EntryCount   int       `json:"entry_count"`   // Number of entries in the glossary
+}
+
+// CreateGlossaryOptions holds the parameters for creating a glossary.
+type CreateGlossaryOptions struct {
+	Name       string            // Name of the glossary
+	SourceLang string            // Language code of the source terms
+	TargetLang string            // Language code of the target terms
+	Entries    map[string]string // Target terms keyed by their source terms
+}
+
+// glossariesResponse wraps one page of the list of glossaries returned from the API.
+type glossariesResponse struct {
+	Glossaries []*Glossary `json:"glossaries"`
+	Next       string      `json:"next,omitempty"` // Cursor of the next page, empty on the last page
+}
+
+// glossaryCache holds the glossary list fetched by TranslateTextWithBestGlossary. It is dropped whenever the
+// client creates or deletes a glossary; changes made by other clients are only seen after such a change.
+type glossaryCache struct {
+	mu   sync.Mutex
+	list []*Glossary // Glossaries of the account, nil if not fetched yet
+}
+
+// get returns the cached glossary list, fetching it with fetch if it is not cached yet.
+func (gc *glossaryCache) get(fetch func() ([]*Glossary, error)) ([]*Glossary, error) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.list == nil {
+		list, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		gc.list = append([]*Glossary{}, list...)
+	}
+	return gc.list, nil
+}
+
+// invalidate drops the cached glossary list.
+func (gc *glossaryCache) invalidate() {
+	gc.mu.Lock()
+	gc.list = nil
+	gc.mu.Unlock()
+}
+
+// CreateGlossary creates a glossary with the given entries and returns its description.
+// It uses a background context; see CreateGlossaryWithContext for details.
+func (c *Client) CreateGlossary(opts CreateGlossaryOptions) (*Glossary, error) {
+	return c.CreateGlossaryWithContext(context.Background(), opts)
+}
+
+// CreateGlossaryWithContext creates a glossary with the given entries and returns its description.
+// As a repeated request would create a duplicate glossary, it is only retried on 429.
+func (c *Client) CreateGlossaryWithContext(ctx context.Context, opts CreateGlossaryOptions) (*Glossary, error) {
+	if strings.TrimSpace(opts.Name) == "" {
+		return nil, errors.New("glossary name is required")
+	}
+	entries, err := encodeGlossaryEntries(opts.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(map[string]string{
+		"name":           opts.Name,
+		"source_lang":    opts.SourceLang,
+		"target_lang":    opts.TargetLang,
+		"entries":        entries,
+		"entries_format": "tsv",
+	})
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/v2/glossaries", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	glossary, err := doJSON[Glossary](c, ctx, markNonIdempotent(req))
+	if err != nil {
+		return nil, err
+	}
+	c.glossaries.invalidate()
+	return glossary, nil
+}
+
+// ListGlossaries retrieves all glossaries stored in the DeepL account.
+// It uses a background context; see ListGlossariesWithContext for details.
+func (c *Client) ListGlossaries() ([]*Glossary, error) {
+	return c.ListGlossariesWithContext(context.Background())
+}
+
+// ListGlossariesWithContext retrieves all glossaries stored in the DeepL account.
+// If the server splits the list into pages, all pages are fetched and combined.
+func (c *Client) ListGlossariesWithContext(ctx context.Context) ([]*Glossary, error) {
+	var glossaries []*Glossary
+	seen := make(map[string]bool)
+	cursor := ""
+	for {
+		u := fmt.Sprintf("%s/v2/glossaries", c.baseURL)
+		if cursor != "" {
+			u += "?" + url.Values{"cursor": {cursor}}.Encode()
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		response, err := doJSON[glossariesResponse](c, ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		glossaries = append(glossaries, response.Glossaries...)
+
+		if response.Next == "" {
+			return glossaries, nil
+		}
+		if seen[response.Next] {
+			return nil, fmt.Errorf("glossary list repeats page cursor %q", response.Next)
+		}
+		seen[response.Next] = true
+		cursor = response.Next
+	}
+}
+
+// GetGlossary retrieves the description of the glossary with the given ID.
+// It uses a background context; see GetGlossaryWithContext for details.
+func (c *Client) GetGlossary(id string) (*Glossary, error) {
+	return c.GetGlossaryWithContext(context.Background(), id)
+}
+
+// GetGlossaryWithContext retrieves the description of the glossary with the given ID.
+func (c *Client) GetGlossaryWithContext(ctx context.Context, id string) (*Glossary, error) {
+	req, err := c.newGlossaryRequest(ctx, http.MethodGet, id, "")
+	if err != nil {
+		return nil, err
+	}
+	return doJSON[Glossary](c, ctx, req)
+}
+
+// GetGlossaries retrieves the descriptions of the glossaries with the given IDs, keyed by ID. The glossaries
+// are fetched concurrently, bounded by the client's maximum concurrency (see WithMaxConcurrency); duplicate IDs
+// are fetched once.
+// A failed request does not stop the others: the errors of all failed IDs are combined with errors.Join and
+// returned along with the glossaries that were fetched successfully.
+func (c *Client) GetGlossaries(ctx context.Context, ids []string) (map[string]*Glossary, error) {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	glossaries := make([]*Glossary, len(unique))
+	errs := make([]error, len(unique))
+	err := c.runConcurrently(ctx, len(unique), func(ctx context.Context, i int) error {
+		glossary, err := c.GetGlossaryWithContext(ctx, unique[i])
+		if err != nil {
+			errs[i] = fmt.Errorf("glossary %s: %w", unique[i], err)
+			return nil
+		}
+		glossaries[i] = glossary
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	result := make(map[string]*Glossary, len(unique))
+	for i, glossary := range glossaries {
+		if glossary != nil {
+			result[unique[i]] = glossary
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// DeleteGlossary deletes the glossary with the given ID.
+// It uses a background context; see DeleteGlossaryWithContext for details.
+func (c *Client) DeleteGlossary(id string) error {
+	return c.DeleteGlossaryWithContext(context.Background(), id)
+}
+
+// DeleteGlossaryWithContext deletes the glossary with the given ID.
+func (c *Client) DeleteGlossaryWithContext(ctx context.Context, id string) error {
+	req, err := c.newGlossaryRequest(ctx, http.MethodDelete, id, "")
+	if err != nil {
+		return err
+	}
+	// DeepL answers with 204 No Content, so there is no body to decode.
+	if err := c.doRequestRaw(ctx, req, func(io.Reader) error { return nil }); err != nil {
+		return err
+	}
+	c.glossaries.invalidate()
+	return nil
+}
+
+// GetGlossaryEntries retrieves the entries of the glossary with the given ID.
+// It uses a background context; see GetGlossaryEntriesWithContext for details.
+func (c *Client) GetGlossaryEntries(id string) (map[string]string, error) {
+	return c.GetGlossaryEntriesWithContext(context.Background(), id)
+}
+
+// GetGlossaryEntriesWithContext retrieves the entries of the glossary with the given ID as target terms keyed by
+// their source terms.
+func (c *Client) GetGlossaryEntriesWithContext(ctx context.Context, id string) (map[string]string, error) {
+	req, err := c.newGlossaryRequest(ctx, http.MethodGet, id, "/entries")
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/tab-separated-values")
+
+	var entries map[string]string
+	err = c.doRequestRaw(ctx, req, func(body io.Reader) error {
+		var err error
+		entries, err = decodeGlossaryEntries(body)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// MergeGlossaries creates a glossary named name from the entries of the glossaries with the given IDs, e.g. to
+// consolidate the terminology of several teams, and returns its description along with the conflicts.
+// The language pair of the source glossaries is not checked; it is up to the caller to pass glossaries for
+// sourceLang and targetLang.
+//
+// Conflicts are resolved last-wins: if a source term has different target terms in several glossaries, the one
+// of the glossary listed last in ids is kept. The overwritten source terms are returned in sorted order; terms with
+// the same target term in several glossaries are no conflict. The source glossaries are left unchanged.
+func (c *Client) MergeGlossaries(ctx context.Context, name, sourceLang, targetLang string, ids []string) (*Glossary, []string, error) {
+	if len(ids) == 0 {
+		return nil, nil, errors.New("at least one glossary ID is required")
+	}
+
+	merged := make(map[string]string)
+	overwritten := make(map[string]bool)
+	for _, id := range ids {
+		entries, err := c.GetGlossaryEntriesWithContext(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("glossary %s: %w", id, err)
+		}
+		for sourceTerm, targetTerm := range entries {
+			if previous, ok := merged[sourceTerm]; ok && previous != targetTerm {
+				overwritten[sourceTerm] = true
+			}
+			merged[sourceTerm] = targetTerm
+		}
+	}
+
+	glossary, err := c.CreateGlossaryWithContext(ctx, CreateGlossaryOptions{
+		Name:       name,
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+		Entries:    merged,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conflicts []string
+	for sourceTerm := range overwritten {
+		conflicts = append(conflicts, sourceTerm)
+	}
+	sort.Strings(conflicts)
+	return glossary, conflicts, nil
+}
+
+// TranslateWithGlossaryVerification translates text into targetLang using the glossary with the given ID and
+// reports whether the glossary was applied. The source language is taken from the glossary.
+//
+// DeepL does not report glossary use, so this is a heuristic: the glossary counts as applied if at least one
+// of its source terms occurs in text and the corresponding target term occurs in the translation, ignoring
+// case. It therefore also reports false if text contains none of the source terms. Fetching the glossary and
+// its entries takes two additional requests.
+func (c *Client) TranslateWithGlossaryVerification(ctx context.Context, text, targetLang, glossaryID string) (*Translation, bool, error) {
+	glossary, err := c.GetGlossaryWithContext(ctx, glossaryID)
+	if err != nil {
+		return nil, false, err
+	}
+	entries, err := c.GetGlossaryEntriesWithContext(ctx, glossaryID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	translations, err := c.TranslateTextWithOptions(ctx, TranslateTextOptions{
+		Text:       []string{text},
+		SourceLang: glossary.SourceLang,
+		TargetLang: targetLang,
+		GlossaryID: glossaryID,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	translation := translations[0]
+
+	source := strings.ToLower(text)
+	translated := strings.ToLower(translation.Text)
+	for sourceTerm, targetTerm := range entries {
+		if strings.Contains(source, strings.ToLower(sourceTerm)) && strings.Contains(translated, strings.ToLower(targetTerm)) {
+			return translation, true, nil
+		}
+	}
+	return translation, false, nil
+}
+
+// TranslateTextWithBestGlossary translates text from sourceLang into targetLang using the glossary of the account
+// for that language pair, so that callers with one glossary per pair need not keep track of glossary IDs.
+//
+// Glossaries match if they are ready and their language codes equal the given ones, ignoring case and regional
+// variants, e.g. a "de" glossary is used for "DE" as well as "DE-CH". It is an error if no glossary or more than
+// one glossary matches. The glossary list is fetched once and cached; it is fetched again after the client
+// creates or deletes a glossary.
+func (c *Client) TranslateTextWithBestGlossary(ctx context.Context, text, sourceLang, targetLang string) (*Translation, error) {
+	source, target := baseLangCode(sourceLang), baseLangCode(targetLang)
+	if source == "" || target == "" {
+		return nil, errors.New("source and target language are required")
+	}
+
+	glossaries, err := c.glossaries.get(func() ([]*Glossary, error) {
+		return c.ListGlossariesWithContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, glossary := range glossaries {
+		if glossary.Ready && baseLangCode(glossary.SourceLang) == source && baseLangCode(glossary.TargetLang) == target {
+			matches = append(matches, glossary.GlossaryID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no glossary for %s to %s", source, target)
+	case 1:
+	default:
+		return nil, fmt.Errorf("several glossaries for %s to %s: %s", source, target, strings.Join(matches, ", "))
+	}
+
+	translations, err := c.TranslateTextWithOptions(ctx, TranslateTextOptions{
+		Text:       []string{text},
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+		GlossaryID: matches[0],
+	})
+	if err != nil {
+		return nil, err
+	}
+	return translations[0], nil
+}
+
+// baseLangCode returns the upper-case language of code without its regional variant, e.g. "EN" for "en-GB".
+func baseLangCode(code string) string {
+	base, _, _ := strings.Cut(strings.ToUpper(strings.TrimSpace(code)), "-")
+	return base
+}
+
+// newGlossaryRequest builds a request to the endpoint of the glossary with the given ID.
+// The suffix is appended to the glossary's path, e.g. "/entries".
+func (c *Client) newGlossaryRequest(ctx context.Context, method, id, suffix string) (*http.Request, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, errors.New("glossary ID is required")
+	}
+	u := fmt.Sprintf("%s/v2/glossaries/%s%s", c.baseURL, url.PathEscape(id), suffix)
+	return http.NewRequestWithContext(ctx, method, u, nil)
+}
+
+// encodeGlossaryEntries returns the entries in the tab-separated format DeepL expects, one entry per line
+// and sorted by source term so that the same entries always yield the same request.
+func encodeGlossaryEntries(entries map[string]string) (string, error) {
+	if len(entries) == 0 {
+		return "", errors.New("glossary requires at least one entry")
+	}
+
+	sources := make([]string, 0, len(entries))
+	for source := range entries {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var b strings.Builder
+	for _, source := range sources {
+		target := entries[source]
+		if strings.TrimSpace(source) == "" || strings.TrimSpace(target) == "" {
+			return "", fmt.Errorf("glossary entry %q -> %q must not be empty", source, target)
+		}
+		if strings.ContainsAny(source, "\t\r\n") || strings.ContainsAny(target, "\t\r\n") {
+			return "", fmt.Errorf("glossary entry %q -> %q must not contain tabs or line breaks", source, target)
+		}
+		b.WriteString(source)
+		b.WriteByte('\t')
+		b.WriteString(target)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// decodeGlossaryEntries parses glossary entries in the tab-separated format, one entry per line.
+// Empty lines are skipped.
+func decodeGlossaryEntries(r io.Reader) (map[string]string, error) {
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		source, target, ok := strings.Cut(text, "\t")
+		if !ok {
+			return nil, fmt.Errorf("invalid glossary entry on line %d: missing tab separator", line)
+		}
+		entries[source] = target
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read glossary entries: %w", err)
+	}
+	return entries, nil
+}
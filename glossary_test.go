@@ -0,0 +1,566 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCreateGlossary(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.Method != http.MethodPost || req.URL.Path != "/v2/glossaries" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		expected := map[string]string{
+			"name":           "Product terms",
+			"source_lang":    "EN",
+			"target_lang":    "DE",
+			"entries":        "Cart\tWarenkorb\nCheckout\tKasse\n",
+			"entries_format": "tsv",
+		}
+		for key, value := range expected {
+			if body[key] != value {
+				t.Errorf("expected %s=%q, got %q", key, value, body[key])
+			}
+		}
+
+		return MockResponse(200, map[string]any{
+			"glossary_id":   "def3a26b-3e84-45b3-84ae-0c0aaf3525f7",
+			"name":          "Product terms",
+			"ready":         true,
+			"source_lang":   "en",
+			"target_lang":   "de",
+			"creation_time": "2021-08-03T14:16:18.329Z",
+			"entry_count":   2,
+		})
+	})
+
+	glossary, err := client.CreateGlossaryWithContext(context.Background(), CreateGlossaryOptions{
+		Name:       "Product terms",
+		SourceLang: "EN",
+		TargetLang: "DE",
+		Entries:    map[string]string{"Checkout": "Kasse", "Cart": "Warenkorb"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if glossary.GlossaryID != "def3a26b-3e84-45b3-84ae-0c0aaf3525f7" || !glossary.Ready || glossary.EntryCount != 2 {
+		t.Errorf("unexpected glossary: %+v", glossary)
+	}
+	expectedTime := time.Date(2021, 8, 3, 14, 16, 18, 329000000, time.UTC)
+	if !glossary.CreationTime.Equal(expectedTime) {
+		t.Errorf("expected creation time %v, got %v", expectedTime, glossary.CreationTime)
+	}
+}
+
+func TestCreateGlossary_InvalidEntries(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Error("should not send a request for invalid entries")
+		return nil
+	})
+
+	testCases := []struct {
+		name    string
+		entries map[string]string
+	}{
+		{"no entries", nil},
+		{"empty target", map[string]string{"Cart": " "}},
+		{"tab in source", map[string]string{"Shopping\tCart": "Warenkorb"}},
+		{"newline in target", map[string]string{"Cart": "Waren\nkorb"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.CreateGlossaryWithContext(context.Background(), CreateGlossaryOptions{
+				Name:       "Terms",
+				SourceLang: "EN",
+				TargetLang: "DE",
+				Entries:    tc.entries,
+			})
+			if err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestListGlossaries(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.Method != http.MethodGet || req.URL.Path != "/v2/glossaries" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		return MockResponse(200, map[string]any{
+			"glossaries": []map[string]any{
+				{"glossary_id": "g1", "name": "One", "ready": true, "source_lang": "en", "target_lang": "de", "entry_count": 3},
+				{"glossary_id": "g2", "name": "Two", "ready": false, "source_lang": "en", "target_lang": "fr", "entry_count": 1},
+			},
+		})
+	})
+
+	glossaries, err := client.ListGlossariesWithContext(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(glossaries) != 2 {
+		t.Fatalf("expected 2 glossaries, got %d", len(glossaries))
+	}
+	if glossaries[1].GlossaryID != "g2" || glossaries[1].TargetLang != "fr" || glossaries[1].Ready {
+		t.Errorf("unexpected glossary: %+v", glossaries[1])
+	}
+}
+
+func TestGetGlossary(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.Method != http.MethodGet || req.URL.Path != "/v2/glossaries/g1" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		return MockResponse(200, map[string]any{"glossary_id": "g1", "name": "One", "ready": true, "entry_count": 3})
+	})
+
+	glossary, err := client.GetGlossaryWithContext(context.Background(), "g1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if glossary.GlossaryID != "g1" || glossary.Name != "One" || glossary.EntryCount != 3 {
+		t.Errorf("unexpected glossary: %+v", glossary)
+	}
+}
+
+func TestGetGlossaries(t *testing.T) {
+	var inFlight, maxInFlight int32
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		id := strings.TrimPrefix(req.URL.Path, "/v2/glossaries/")
+		return MockResponse(200, Glossary{GlossaryID: id, Name: "Glossary " + id, Ready: true})
+	})
+
+	glossaries, err := client.GetGlossaries(context.Background(), []string{"g1", "g2", "g3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(glossaries) != 3 {
+		t.Fatalf("expected 3 glossaries, got %d", len(glossaries))
+	}
+	for _, id := range []string{"g1", "g2", "g3"} {
+		if glossaries[id] == nil || glossaries[id].Name != "Glossary "+id {
+			t.Errorf("unexpected glossary for %s: %+v", id, glossaries[id])
+		}
+	}
+	if maxInFlight < 2 {
+		t.Errorf("expected glossaries to be fetched concurrently, got at most %d in flight", maxInFlight)
+	}
+}
+
+func TestGetGlossaries_CollectsErrors(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		id := strings.TrimPrefix(req.URL.Path, "/v2/glossaries/")
+		if id != "g2" {
+			return MockResponse(404, map[string]string{"message": "Glossary not found"})
+		}
+		return MockResponse(200, Glossary{GlossaryID: id})
+	})
+
+	glossaries, err := client.GetGlossaries(context.Background(), []string{"g1", "g2", "g3"})
+	if err == nil || !strings.Contains(err.Error(), "glossary g1") || !strings.Contains(err.Error(), "glossary g3") {
+		t.Errorf("expected errors for g1 and g3, got %v", err)
+	}
+	if len(glossaries) != 1 || glossaries["g2"] == nil {
+		t.Errorf("expected only g2 to be returned, got %v", glossaries)
+	}
+}
+
+func TestDeleteGlossary(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.Method != http.MethodDelete || req.URL.Path != "/v2/glossaries/g1" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Header: make(http.Header)}
+	})
+
+	if err := client.DeleteGlossaryWithContext(context.Background(), "g1"); err != nil {
+		t.Errorf("expected 204 No Content to be treated as success, got %v", err)
+	}
+}
+
+func TestDeleteGlossary_NotFound(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(404, map[string]string{"message": "Glossary not found"})
+	})
+
+	err := client.DeleteGlossaryWithContext(context.Background(), "missing")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		t.Errorf("expected *APIError with status 404, got %v", err)
+	}
+}
+
+func TestGetGlossaryEntries(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path != "/v2/glossaries/g1/entries" {
+			t.Errorf("unexpected path: %s", req.URL.Path)
+		}
+		if accept := req.Header.Get("Accept"); accept != "text/tab-separated-values" {
+			t.Errorf("expected Accept 'text/tab-separated-values', got %q", accept)
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("Cart\tWarenkorb\r\nCheckout\tKasse\n\nShopping list\tEinkaufsliste\n")),
+			Header:     http.Header{"Content-Type": {"text/tab-separated-values"}},
+		}
+	})
+
+	entries, err := client.GetGlossaryEntriesWithContext(context.Background(), "g1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{"Cart": "Warenkorb", "Checkout": "Kasse", "Shopping list": "Einkaufsliste"}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected %v, got %v", expected, entries)
+	}
+}
+
+func TestGetGlossaryEntries_Malformed(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("Cart\tWarenkorb\nCheckout\n")), Header: make(http.Header)}
+	})
+
+	_, err := client.GetGlossaryEntriesWithContext(context.Background(), "g1")
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error for line 2, got %v", err)
+	}
+}
+
+func TestListGlossaries_Pagination(t *testing.T) {
+	var cursors []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		cursor := req.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+		if cursor == "" {
+			return MockResponse(200, map[string]any{
+				"glossaries": []map[string]any{{"glossary_id": "g1"}, {"glossary_id": "g2"}},
+				"next":       "page-2",
+			})
+		}
+		return MockResponse(200, map[string]any{
+			"glossaries": []map[string]any{{"glossary_id": "g3"}},
+		})
+	})
+
+	glossaries, err := client.ListGlossariesWithContext(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, glossary := range glossaries {
+		ids = append(ids, glossary.GlossaryID)
+	}
+	if !reflect.DeepEqual(ids, []string{"g1", "g2", "g3"}) {
+		t.Errorf("expected glossaries of both pages, got %v", ids)
+	}
+	if !reflect.DeepEqual(cursors, []string{"", "page-2"}) {
+		t.Errorf("expected requests for the first page and cursor page-2, got %q", cursors)
+	}
+}
+
+func TestListGlossaries_RepeatedCursor(t *testing.T) {
+	requests := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		return MockResponse(200, map[string]any{"glossaries": []map[string]any{}, "next": "same"})
+	})
+
+	if _, err := client.ListGlossariesWithContext(context.Background()); err == nil {
+		t.Error("expected error for a repeated cursor, got nil")
+	}
+	if requests != 2 {
+		t.Errorf("expected pagination to stop after 2 requests, got %d", requests)
+	}
+}
+
+func TestListGlossaries_ContextCancelledBetweenPages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Query().Get("cursor") != "" {
+			return nil
+		}
+		cancel()
+		return MockResponse(200, map[string]any{"glossaries": []map[string]any{{"glossary_id": "g1"}}, "next": "page-2"})
+	})
+
+	_, err := client.ListGlossariesWithContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGlossaryRequests_AcceptHeader(t *testing.T) {
+	var accepts []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		accepts = append(accepts, req.Header.Get("Accept"))
+		if strings.HasSuffix(req.URL.Path, "/entries") {
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("Cart\tWarenkorb\n")), Header: make(http.Header)}
+		}
+		return MockResponse(200, map[string]any{"glossary_id": "g1"})
+	})
+
+	if _, err := client.GetGlossaryWithContext(context.Background(), "g1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetGlossaryEntriesWithContext(context.Background(), "g1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"application/json", "text/tab-separated-values"}
+	if !reflect.DeepEqual(accepts, expected) {
+		t.Errorf("expected Accept headers %q, got %q", expected, accepts)
+	}
+}
+
+func TestGlossaryMethods_ContextCanceled(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := map[string]func() error{
+		"CreateGlossaryWithContext": func() error {
+			_, err := client.CreateGlossaryWithContext(ctx, CreateGlossaryOptions{Name: "Terms", SourceLang: "EN", TargetLang: "DE", Entries: map[string]string{"Cart": "Warenkorb"}})
+			return err
+		},
+		"ListGlossariesWithContext": func() error {
+			_, err := client.ListGlossariesWithContext(ctx)
+			return err
+		},
+		"GetGlossaryWithContext": func() error {
+			_, err := client.GetGlossaryWithContext(ctx, "g1")
+			return err
+		},
+		"DeleteGlossaryWithContext": func() error {
+			return client.DeleteGlossaryWithContext(ctx, "g1")
+		},
+		"GetGlossaryEntriesWithContext": func() error {
+			_, err := client.GetGlossaryEntriesWithContext(ctx, "g1")
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+}
+
+func TestGlossaryMethods_BackgroundContext(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.Method == http.MethodDelete {
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Header: make(http.Header)}
+		}
+		return MockResponse(200, map[string]any{"glossary_id": "g1", "glossaries": []map[string]any{}})
+	})
+
+	if _, err := client.GetGlossary("g1"); err != nil {
+		t.Errorf("GetGlossary: unexpected error: %v", err)
+	}
+	if _, err := client.ListGlossaries(); err != nil {
+		t.Errorf("ListGlossaries: unexpected error: %v", err)
+	}
+	if err := client.DeleteGlossary("g1"); err != nil {
+		t.Errorf("DeleteGlossary: unexpected error: %v", err)
+	}
+}
+
+func TestTranslateWithGlossaryVerification(t *testing.T) {
+	testCases := []struct {
+		name       string
+		translated string
+		expected   bool
+	}{
+		{"term substituted", "Zum Warenkorb hinzufügen", true},
+		{"term not substituted", "Zum Einkaufswagen hinzufügen", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				switch req.URL.Path {
+				case "/v2/glossaries/g1":
+					return MockResponse(200, Glossary{GlossaryID: "g1", SourceLang: "en", TargetLang: "de", Ready: true})
+				case "/v2/glossaries/g1/entries":
+					return &http.Response{
+						StatusCode: 200,
+						Body:       io.NopCloser(strings.NewReader("Cart\tWarenkorb\nCheckout\tKasse\n")),
+						Header:     make(http.Header),
+					}
+				case "/v2/translate":
+					body, _ := io.ReadAll(req.Body)
+					var opts TranslateTextOptions
+					_ = json.Unmarshal(body, &opts)
+					if opts.GlossaryID != "g1" || opts.SourceLang != "EN" {
+						t.Errorf("expected glossary g1 with source language EN, got %q and %q", opts.GlossaryID, opts.SourceLang)
+					}
+					return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: tc.translated}}})
+				}
+				t.Errorf("unexpected path: %s", req.URL.Path)
+				return MockResponse(404, nil)
+			})
+
+			translation, applied, err := client.TranslateWithGlossaryVerification(context.Background(), "Add to cart", "DE", "g1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if translation.Text != tc.translated {
+				t.Errorf("expected translation %q, got %q", tc.translated, translation.Text)
+			}
+			if applied != tc.expected {
+				t.Errorf("expected glossary applied %v, got %v", tc.expected, applied)
+			}
+		})
+	}
+}
+
+func TestTranslateTextWithBestGlossary(t *testing.T) {
+	listed := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/v2/glossaries":
+			listed++
+			return MockResponse(200, glossariesResponse{Glossaries: []*Glossary{
+				{GlossaryID: "en-fr", SourceLang: "en", TargetLang: "fr", Ready: true},
+				{GlossaryID: "de-en", SourceLang: "de", TargetLang: "en", Ready: true},
+				{GlossaryID: "en-de", SourceLang: "en", TargetLang: "de", Ready: true},
+				{GlossaryID: "en-de-draft", SourceLang: "en", TargetLang: "de", Ready: false},
+			}})
+		case "/v2/translate":
+			body, _ := io.ReadAll(req.Body)
+			var opts TranslateTextOptions
+			_ = json.Unmarshal(body, &opts)
+			if opts.GlossaryID != "en-de" || opts.SourceLang != "EN" {
+				t.Errorf("expected glossary en-de with source language EN, got %q and %q", opts.GlossaryID, opts.SourceLang)
+			}
+			return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Zum Warenkorb hinzufügen"}}})
+		}
+		t.Errorf("unexpected path: %s", req.URL.Path)
+		return MockResponse(404, nil)
+	})
+
+	for i := 0; i < 2; i++ {
+		translation, err := client.TranslateTextWithBestGlossary(context.Background(), "Add to cart", "en", "DE-CH")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if translation.Text != "Zum Warenkorb hinzufügen" {
+			t.Errorf("unexpected translation: %q", translation.Text)
+		}
+	}
+	if listed != 1 {
+		t.Errorf("expected the glossary list to be fetched once, got %d", listed)
+	}
+}
+
+func TestTranslateTextWithBestGlossary_NoUniqueMatch(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path != "/v2/glossaries" {
+			t.Errorf("unexpected request to %s", req.URL.Path)
+			return MockResponse(404, nil)
+		}
+		return MockResponse(200, glossariesResponse{Glossaries: []*Glossary{
+			{GlossaryID: "g1", SourceLang: "en", TargetLang: "de", Ready: true},
+			{GlossaryID: "g2", SourceLang: "EN", TargetLang: "DE", Ready: true},
+		}})
+	})
+
+	_, err := client.TranslateTextWithBestGlossary(context.Background(), "Add to cart", "EN", "DE")
+	if err == nil || !strings.Contains(err.Error(), "g1, g2") {
+		t.Errorf("expected an error naming both glossaries, got %v", err)
+	}
+	if _, err := client.TranslateTextWithBestGlossary(context.Background(), "Add to cart", "EN", "FR"); err == nil {
+		t.Error("expected error for a language pair without glossary")
+	}
+	if _, err := client.TranslateTextWithBestGlossary(context.Background(), "Add to cart", "", "DE"); err == nil {
+		t.Error("expected error for missing source language")
+	}
+}
+
+func TestMergeGlossaries(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/v2/glossaries/g1/entries":
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader("Cart\tWarenkorb\nCheckout\tKasse\n")),
+				Header:     make(http.Header),
+			}
+		case "/v2/glossaries/g2/entries":
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader("Cart\tEinkaufswagen\nCheckout\tKasse\nInvoice\tRechnung\n")),
+				Header:     make(http.Header),
+			}
+		case "/v2/glossaries":
+			var body map[string]string
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			expected := map[string]string{
+				"name":        "Merged terms",
+				"source_lang": "EN",
+				"target_lang": "DE",
+				"entries":     "Cart\tEinkaufswagen\nCheckout\tKasse\nInvoice\tRechnung\n",
+			}
+			for key, value := range expected {
+				if body[key] != value {
+					t.Errorf("expected %s=%q, got %q", key, value, body[key])
+				}
+			}
+			return MockResponse(200, Glossary{GlossaryID: "g3", Name: "Merged terms", EntryCount: 3})
+		}
+		t.Errorf("unexpected path: %s", req.URL.Path)
+		return MockResponse(404, nil)
+	})
+
+	glossary, conflicts, err := client.MergeGlossaries(context.Background(), "Merged terms", "EN", "DE", []string{"g1", "g2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if glossary.GlossaryID != "g3" || glossary.EntryCount != 3 {
+		t.Errorf("unexpected glossary: %+v", glossary)
+	}
+	if !reflect.DeepEqual(conflicts, []string{"Cart"}) {
+		t.Errorf("expected conflict for Cart, got %q", conflicts)
+	}
+}
+
+func TestMergeGlossaries_NoIDs(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Error("expected no request to be sent")
+		return nil
+	})
+
+	if _, _, err := client.MergeGlossaries(context.Background(), "Merged terms", "EN", "DE", nil); err == nil {
+		t.Error("expected error for missing glossary IDs")
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/lkretschmer/deepl-go
 
 go 1.20
+
+require golang.org/x/text v0.22.0
@@ -5,6 +5,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+)
+
+// Language types accepted by GetLanguage.
+const (
+	LanguageTypeSource = "source"
+	LanguageTypeTarget = "target"
 )
 
 // Language represents a language supported by the DeepL API, including its code, display name, and formality support.
@@ -36,8 +44,98 @@ func (c *Client) GetSourceLanguagesWithContext(ctx context.Context) ([]*Language
 	return c.getLanguages(ctx, url.Values{"type": {"source"}})
 }
 
+// GetLanguage retrieves a single supported language by its code, e.g. "DE" or "en-us".
+// The langType must be either LanguageTypeSource or LanguageTypeTarget. The code is matched case-insensitively
+// and an error is returned if DeepL does not list the language for the given type.
+func (c *Client) GetLanguage(ctx context.Context, code string, langType string) (*Language, error) {
+	if langType != LanguageTypeSource && langType != LanguageTypeTarget {
+		return nil, fmt.Errorf("invalid language type %q: must be %q or %q", langType, LanguageTypeSource, LanguageTypeTarget)
+	}
+
+	languages, err := c.getLanguages(ctx, url.Values{"type": {langType}})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lang := range languages {
+		if strings.EqualFold(lang.Language, code) {
+			return lang, nil
+		}
+	}
+	return nil, fmt.Errorf("%s language %q not found", langType, code)
+}
+
+// qualityOptimizedTargetLangs lists the base target languages for which DeepL offers its next-gen
+// "quality_optimized" model. DeepL has no endpoint reporting model availability, so this list follows
+// the API documentation and must be extended when DeepL adds next-gen support for further languages.
+var qualityOptimizedTargetLangs = map[string]bool{
+	"DE": true, "EN": true, "ES": true, "FR": true, "IT": true, "JA": true,
+	"KO": true, "NL": true, "PL": true, "PT": true, "ZH": true,
+}
+
+// GetSupportedModels returns the model_type values DeepL accepts for translating from source into target.
+// They equal the String values of the ModelType constants to set in TranslateTextOptions.Model.
+// An empty source stands for source language auto-detection.
+// Both languages are first checked against the languages DeepL lists, so an unsupported pair yields an
+// error. As DeepL does not expose model availability, the models are derived from a static list.
+func (c *Client) GetSupportedModels(ctx context.Context, source, target string) ([]string, error) {
+	if source != "" {
+		if _, err := c.GetLanguage(ctx, source, LanguageTypeSource); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := c.GetLanguage(ctx, target, LanguageTypeTarget); err != nil {
+		return nil, err
+	}
+
+	models := []string{ModelTypeLatencyOptimized.String(), ModelTypePreferQualityOptimized.String()}
+	base, _, _ := strings.Cut(strings.ToUpper(target), "-")
+	if qualityOptimizedTargetLangs[base] {
+		models = append(models, ModelTypeQualityOptimized.String())
+	}
+	return models, nil
+}
+
+// languageCache holds the language lists fetched by a client created with WithLanguageCache.
+type languageCache struct {
+	mu    sync.Mutex
+	lists map[string][]*Language // Language lists keyed by their encoded query parameters
+}
+
+// WithLanguageCache returns an Option that caches the source and target language lists for the lifetime
+// of the client, so that GetLanguage, GetSupportedModels, and similar helpers fetch each list only once.
+// The cache also enables suggestions for invalid target languages in translation errors.
+func WithLanguageCache() Option {
+	return func(c *Client) {
+		c.languages = &languageCache{lists: make(map[string][]*Language)}
+	}
+}
+
+// cachedLanguages returns the cached list of the given language type, or nil if it has not been fetched
+// yet or the client has no language cache.
+func (c *Client) cachedLanguages(langType string) []*Language {
+	if c.languages == nil {
+		return nil
+	}
+	c.languages.mu.Lock()
+	defer c.languages.mu.Unlock()
+	return c.languages.lists[url.Values{"type": {langType}}.Encode()]
+}
+
 // getLanguages is an internal method that fetches either source or target languages from the DeepL API.
+// As a read-only request, it is retried on transient failures according to the client's retry policy.
+// With WithLanguageCache, a list fetched before is returned from the cache.
 func (c *Client) getLanguages(ctx context.Context, v url.Values) ([]*Language, error) {
+	key := v.Encode()
+	if c.languages != nil {
+		c.languages.mu.Lock()
+		cached, ok := c.languages.lists[key]
+		c.languages.mu.Unlock()
+		if ok {
+			return append([]*Language(nil), cached...), nil
+		}
+	}
+
 	u := fmt.Sprintf("%s/v2/languages?", c.baseURL)
 
 	// Construct a POST request with the query parameters appended to the URL.
@@ -46,11 +144,62 @@ func (c *Client) getLanguages(ctx context.Context, v url.Values) ([]*Language, e
 		return nil, err
 	}
 
-	var languages []*Language
-
 	// Send the request and decode the response JSON into languages slice.
-	if err := c.doRequest(ctx, req, &languages); err != nil {
+	languages, err := doJSON[[]*Language](c, ctx, req)
+	if err != nil {
 		return nil, err
 	}
-	return languages, nil
+	if err := checkNotEmpty(*languages, "languages"); err != nil {
+		return nil, err
+	}
+
+	if c.languages != nil {
+		c.languages.mu.Lock()
+		c.languages.lists[key] = *languages
+		c.languages.mu.Unlock()
+		return append([]*Language(nil), *languages...), nil
+	}
+	return *languages, nil
+}
+
+// suggestLanguage returns the code in languages closest to code by edit distance, or an empty string
+// if none is within two edits, e.g. "DE" for "DEU".
+func suggestLanguage(code string, languages []*Language) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	best, bestDistance := "", 3
+	for _, lang := range languages {
+		if d := levenshtein(code, strings.ToUpper(lang.Language)); d < bestDistance {
+			best, bestDistance = lang.Language, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the number of single-character insertions, deletions, and substitutions
+// needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
 }
@@ -2,9 +2,12 @@ package deepl
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetSourceLanguages(t *testing.T) {
@@ -128,3 +131,208 @@ func TestGetLanguagesError(t *testing.T) {
 		t.Error("expected error from GetTargetLanguages, got nil")
 	}
 }
+
+func TestGetLanguage(t *testing.T) {
+	sourceLanguages := []*Language{
+		{Language: "EN", Name: "English", SupportsFormality: false},
+		{Language: "DE", Name: "German", SupportsFormality: true},
+	}
+	targetLanguages := []*Language{
+		{Language: "EN-US", Name: "English (American)", SupportsFormality: false},
+		{Language: "DE", Name: "German", SupportsFormality: true},
+	}
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Query().Get("type") == "target" {
+			return MockResponse(200, targetLanguages)
+		}
+		return MockResponse(200, sourceLanguages)
+	})
+
+	testCases := []struct {
+		name     string
+		code     string
+		langType string
+		expected string
+	}{
+		{"source found", "de", LanguageTypeSource, "DE"},
+		{"target found", "en-us", LanguageTypeTarget, "EN-US"},
+		{"source not found", "EN-US", LanguageTypeSource, ""},
+		{"target not found", "XX", LanguageTypeTarget, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lang, err := client.GetLanguage(context.Background(), tc.code, tc.langType)
+			if tc.expected == "" {
+				if err == nil || !strings.Contains(err.Error(), "not found") {
+					t.Errorf("expected not found error, got lang %+v and error %v", lang, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lang.Language != tc.expected {
+				t.Errorf("expected language %s, got %s", tc.expected, lang.Language)
+			}
+		})
+	}
+}
+
+func TestGetLanguage_InvalidType(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Fatal("should not send request for an invalid language type")
+		return nil
+	})
+
+	_, err := client.GetLanguage(context.Background(), "DE", "both")
+	if err == nil || !strings.Contains(err.Error(), "invalid language type") {
+		t.Errorf("expected invalid language type error, got %v", err)
+	}
+}
+
+func TestGetSourceLanguages_RetryOn503ThenSuccess(t *testing.T) {
+	attempt := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		attempt++
+		if attempt == 1 {
+			return MockResponse(503, map[string]string{"message": "service unavailable"})
+		}
+		return MockResponse(200, []*Language{{Language: "EN", Name: "English"}})
+	})
+	client.retryPolicy = retryPolicy{MaxRetries: 3, MaxDelay: 10 * time.Millisecond}
+
+	languages, err := client.GetSourceLanguages()
+	if err != nil {
+		t.Fatalf("expected success after retry, got error %v", err)
+	}
+	if attempt != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempt)
+	}
+	if len(languages) != 1 || languages[0].Language != "EN" {
+		t.Errorf("unexpected languages: %+v", languages)
+	}
+}
+
+func TestGetSupportedModels(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Query().Get("type") == LanguageTypeSource {
+			return MockResponse(200, []*Language{{Language: "EN"}, {Language: "DE"}})
+		}
+		return MockResponse(200, []*Language{{Language: "DE"}, {Language: "EN-US"}, {Language: "UK"}})
+	})
+
+	testCases := []struct {
+		name     string
+		source   string
+		target   string
+		expected []string
+	}{
+		{"next-gen target", "EN", "DE", []string{"latency_optimized", "prefer_quality_optimized", "quality_optimized"}},
+		{"regional variant", "DE", "en-us", []string{"latency_optimized", "prefer_quality_optimized", "quality_optimized"}},
+		{"auto-detected source", "", "DE", []string{"latency_optimized", "prefer_quality_optimized", "quality_optimized"}},
+		{"classic target", "EN", "UK", []string{"latency_optimized", "prefer_quality_optimized"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			models, err := client.GetSupportedModels(context.Background(), tc.source, tc.target)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(models, tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, models)
+			}
+		})
+	}
+}
+
+func TestGetSupportedModels_UnsupportedPair(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, []*Language{{Language: "DE"}})
+	})
+
+	_, err := client.GetSupportedModels(context.Background(), "DE", "XX")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error for unsupported target, got %v", err)
+	}
+}
+
+func TestWithLanguageCache(t *testing.T) {
+	requests := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		return MockResponse(200, []*Language{{Language: "DE"}, {Language: "EN-US"}})
+	})
+	WithLanguageCache()(client)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetLanguage(context.Background(), "DE", LanguageTypeTarget); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected the target languages to be fetched once, got %d requests", requests)
+	}
+
+	if _, err := client.GetSourceLanguages(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected source languages to be fetched separately, got %d requests", requests)
+	}
+}
+
+func TestSuggestLanguage(t *testing.T) {
+	languages := []*Language{{Language: "DE"}, {Language: "EN-GB"}, {Language: "EN-US"}, {Language: "JA"}}
+
+	testCases := []struct {
+		code     string
+		expected string
+	}{
+		{"DEU", "DE"},
+		{"en-gbr", "EN-GB"},
+		{"EN_US", "EN-US"},
+		{"JP", "JA"},
+		{"CHINESE", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := suggestLanguage(tc.code, languages); got != tc.expected {
+			t.Errorf("suggestLanguage(%q): expected %q, got %q", tc.code, tc.expected, got)
+		}
+	}
+}
+
+func TestTranslateText_SuggestsTargetLanguage(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if strings.Contains(req.URL.Path, "/v2/languages") {
+			return MockResponse(200, []*Language{{Language: "DE"}, {Language: "FR"}})
+		}
+		return MockResponse(400, map[string]string{"message": "Value for 'target_lang' not supported."})
+	})
+
+	_, err := client.TranslateText("Hello", "DEU")
+	if err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("expected no suggestion without a language cache, got %v", err)
+	}
+
+	WithLanguageCache()(client)
+	_, err = client.TranslateText("Hello", "DEU")
+	if err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("expected no suggestion before the languages were fetched, got %v", err)
+	}
+
+	if _, err := client.GetTargetLanguages(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = client.TranslateText("Hello", "DEU")
+	if err == nil || !strings.Contains(err.Error(), "did you mean DE?") {
+		t.Errorf("expected suggestion for DE, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		t.Errorf("expected the *APIError to be preserved, got %v", err)
+	}
+}
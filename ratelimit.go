@@ -0,0 +1,151 @@
+package deepl
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// adaptiveRecoveryInterval is how long a rate reduced by WithAdaptiveRateLimit stays in effect before it is
+	// doubled again, up to the configured rate.
+	adaptiveRecoveryInterval = 10 * time.Second
+
+	// adaptiveMinRateDivisor bounds the reduction by WithAdaptiveRateLimit to this fraction of the configured rate.
+	adaptiveMinRateDivisor = 16
+)
+
+// WithRateLimit returns an Option that limits the rate of requests sent by the client to requestsPerSecond,
+// allowing bursts of up to burst requests, e.g. to stay below DeepL's limits under parallel load instead of
+// provoking 429 responses. Every attempt of a request, including retries, waits for its turn; a call whose
+// context is done while waiting fails with the context's error. A burst below 1 is treated as 1, and a
+// requestsPerSecond of zero or less removes the limit, which is the default.
+func WithRateLimit(requestsPerSecond int, burst int) Option {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			c.rateLimiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.rateLimiter = newRateLimiter(float64(requestsPerSecond), float64(burst))
+	}
+}
+
+// WithAdaptiveRateLimit returns an Option that lets the rate limit of WithRateLimit react to DeepL's feedback,
+// e.g. for sustained bulk workloads that keep hitting the limits. When a 429 response carries a Retry-After
+// header, the limiter lets no further request pass before the time it asks for, capped at the retry policy's
+// maximum delay, and halves its rate, down to 1/16 of the configured rate. Every 10 seconds without another
+// such response, the rate doubles again until it is back at the configured rate.
+// It has no effect without WithRateLimit, and options can be given in any order.
+func WithAdaptiveRateLimit() Option {
+	return func(c *Client) {
+		c.adaptiveRateLimit = true
+	}
+}
+
+// adaptRateLimit throttles the client's rate limiter after resp if WithAdaptiveRateLimit is enabled and resp is
+// a 429 response with a Retry-After header.
+func (c *Client) adaptRateLimit(resp *http.Response) {
+	if !c.adaptiveRateLimit || c.rateLimiter == nil || resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	now := time.Now()
+	retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	if !ok {
+		return
+	}
+	if retryAfter > c.retryPolicy.MaxDelay {
+		retryAfter = c.retryPolicy.MaxDelay
+	}
+	c.rateLimiter.throttle(now, retryAfter)
+}
+
+// rateLimiter is a token bucket that refills at rate tokens per second up to burst tokens.
+// The number of tokens may become negative, representing requests that wait for a token already reserved.
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64   // Tokens added per second
+	maxRate     float64   // Configured rate, which a rate reduced by throttle recovers to
+	burst       float64   // Maximum number of tokens
+	tokens      float64   // Tokens available after the last update
+	last        time.Time // Time of the last update of tokens
+	throttledAt time.Time // Time the rate was last reduced or recovered by a step
+}
+
+// newRateLimiter returns a rateLimiter whose bucket starts full.
+func newRateLimiter(rate, burst float64) *rateLimiter {
+	return &rateLimiter{rate: rate, maxRate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait blocks until a token is available or ctx is done. A token reserved by a call that gives up
+// is returned to the bucket.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token at now and returns how long the caller has to wait until the token is due.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// throttle halves the rate, bounded by adaptiveMinRateDivisor, and withholds tokens so that the next
+// reservation is not due before now+pause.
+func (l *rateLimiter) throttle(now time.Time, pause time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	l.rate /= 2
+	if minRate := l.maxRate / adaptiveMinRateDivisor; l.rate < minRate {
+		l.rate = minRate
+	}
+	l.throttledAt = now
+	if tokens := 1 - pause.Seconds()*l.rate; l.tokens > tokens {
+		l.tokens = tokens
+	}
+}
+
+// refill adds the tokens accrued since the last update and doubles a reduced rate for every
+// adaptiveRecoveryInterval passed since it was last changed. The caller must hold l.mu.
+func (l *rateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+	for l.rate < l.maxRate && now.Sub(l.throttledAt) >= adaptiveRecoveryInterval {
+		l.rate *= 2
+		if l.rate > l.maxRate {
+			l.rate = l.maxRate
+		}
+		l.throttledAt = l.throttledAt.Add(adaptiveRecoveryInterval)
+	}
+}
@@ -0,0 +1,166 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit_ThrottlesConcurrentRequests(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+	})
+	WithRateLimit(2, 2)(client)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.TranslateText("Hello", "DE")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	elapsed := time.Since(start)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// A burst of 2 passes immediately, the remaining 8 requests follow at 2 per second.
+	if elapsed < 3500*time.Millisecond {
+		t.Errorf("expected 10 requests at 2/s to take about 4s, took %v", elapsed)
+	}
+	if elapsed > 6*time.Second {
+		t.Errorf("expected 10 requests at 2/s to take about 4s, took %v", elapsed)
+	}
+}
+
+func TestWithRateLimit_ContextCancelledWhileWaiting(t *testing.T) {
+	requests := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+	})
+	WithRateLimit(1, 1)(client)
+
+	if _, err := client.TranslateText("Hello", "DE"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.TranslateTextWithContext(ctx, "Hello", "DE")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded while waiting for the limit, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the second request not to be sent, got %d requests", requests)
+	}
+}
+
+func TestWithRateLimit_Disabled(t *testing.T) {
+	client := NewTestClient(nil)
+	WithRateLimit(2, 2)(client)
+	WithRateLimit(0, 0)(client)
+
+	if client.rateLimiter != nil {
+		t.Error("expected a rate of zero to remove the limit")
+	}
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	now := time.Now()
+	limiter := &rateLimiter{rate: 2, burst: 2, tokens: 2, last: now}
+
+	expected := []time.Duration{0, 0, 500 * time.Millisecond, time.Second}
+	for i, want := range expected {
+		if got := limiter.reserve(now); got != want {
+			t.Errorf("reservation %d: expected delay %v, got %v", i, want, got)
+		}
+	}
+
+	// After two seconds, four tokens were added, settling the two reservations and refilling the burst.
+	if got := limiter.reserve(now.Add(2 * time.Second)); got != 0 {
+		t.Errorf("expected no delay after the bucket refilled, got %v", got)
+	}
+}
+
+func TestRateLimiter_ThrottleAndRecover(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiter(8, 1)
+	limiter.last = now
+
+	limiter.throttle(now, 2*time.Second)
+	if limiter.rate != 4 {
+		t.Errorf("expected the rate to be halved to 4, got %v", limiter.rate)
+	}
+	if got := limiter.reserve(now); got != 2*time.Second {
+		t.Errorf("expected the next token to be due after the pause of 2s, got %v", got)
+	}
+
+	for i := 0; i < 10; i++ {
+		limiter.throttle(now, 0)
+	}
+	if limiter.rate != 0.5 {
+		t.Errorf("expected the rate not to drop below 1/16 of the configured rate, got %v", limiter.rate)
+	}
+
+	limiter.reserve(now.Add(adaptiveRecoveryInterval))
+	if limiter.rate != 1 {
+		t.Errorf("expected the rate to double after the recovery interval, got %v", limiter.rate)
+	}
+	limiter.reserve(now.Add(10 * adaptiveRecoveryInterval))
+	if limiter.rate != 8 {
+		t.Errorf("expected the rate to recover to the configured rate, got %v", limiter.rate)
+	}
+}
+
+func TestWithAdaptiveRateLimit_TooManyRequestsReducesThroughput(t *testing.T) {
+	for _, adaptive := range []bool{false, true} {
+		requests := 0
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			requests++
+			if requests == 1 {
+				resp := MockResponse(429, map[string]string{"message": "Too many requests"})
+				resp.Header.Set("Retry-After", "1")
+				return resp
+			}
+			return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+		})
+		client.retryPolicy = retryPolicy{MaxRetries: 0, MaxDelay: 5 * time.Second}
+		WithRateLimit(20, 1)(client)
+		if adaptive {
+			WithAdaptiveRateLimit()(client)
+		}
+
+		if _, err := client.TranslateText("Hello", "DE"); !IsRateLimited(err) {
+			t.Fatalf("adaptive %v: expected a rate limit error, got %v", adaptive, err)
+		}
+
+		start := time.Now()
+		for i := 0; i < 4; i++ {
+			if _, err := client.TranslateText("Hello", "DE"); err != nil {
+				t.Fatalf("adaptive %v: unexpected error: %v", adaptive, err)
+			}
+		}
+		elapsed := time.Since(start)
+
+		// At 20/s, four requests take about 200ms. After the 429, the first one waits for the Retry-After of 1s
+		// and the others follow at the halved rate of 10/s.
+		if adaptive && elapsed < 1200*time.Millisecond {
+			t.Errorf("expected the 429 to slow down the following requests, took %v", elapsed)
+		}
+		if !adaptive && elapsed > 800*time.Millisecond {
+			t.Errorf("expected the following requests not to be slowed down without adaptation, took %v", elapsed)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // WritingStyle represents the desired style in which the text should be rephrased.
@@ -27,18 +28,26 @@ const (
 	WritingStylePreferSimple
 )
 
-// String returns the string representation of the WritingStyle enum.
+// writingStyles lists the API values of the WritingStyle enum, indexed by their enum value.
+var writingStyles = [...]string{
+	"", "academic", "business", "casual", "default", "simple",
+	"prefer_academic", "prefer_business", "prefer_casual", "prefer_simple",
+}
+
+// String returns the string representation of the WritingStyle enum, or an empty string for unknown values.
 func (ws WritingStyle) String() string {
-	styles := [...]string{
-		"academic", "business", "casual", "default", "simple",
-		"prefer_academic", "prefer_business", "prefer_casual", "prefer_simple",
+	if ws < 0 || int(ws) >= len(writingStyles) {
+		return ""
 	}
-	return styles[ws]
+	return writingStyles[ws]
 }
 
 // MarshalJSON implements the json.Marshaler interface for WritingStyle.
 // It serializes the WritingStyle value as its string representation.
 func (ws WritingStyle) MarshalJSON() ([]byte, error) {
+	if ws < 0 || int(ws) >= len(writingStyles) {
+		return nil, fmt.Errorf("invalid writing style value %d", ws)
+	}
 	return json.Marshal(ws.String())
 }
 
@@ -60,25 +69,33 @@ const (
 	WritingTonePreferFriendly
 )
 
-// String returns the string representation of the WritingTone enum.
+// writingTones lists the API values of the WritingTone enum, indexed by their enum value.
+var writingTones = [...]string{
+	"", "confident", "default", "diplomatic", "enthusiastic", "friendly",
+	"prefer_confident", "prefer_diplomatic", "prefer_enthusiastic",
+	"prefer_friendly",
+}
+
+// String returns the string representation of the WritingTone enum, or an empty string for unknown values.
 func (wt WritingTone) String() string {
-	tones := [...]string{
-		"confident", "default", "diplomatic", "enthusiastic", "friendly",
-		"prefer_confident", "prefer_diplomatic", "prefer_enthusiastic",
-		"prefer_friendly",
+	if wt < 0 || int(wt) >= len(writingTones) {
+		return ""
 	}
-	return tones[wt]
+	return writingTones[wt]
 }
 
 // MarshalJSON implements the json.Marshaler interface for WritingTone.
 // It serializes the WritingTone value as its string representation.
 func (wt WritingTone) MarshalJSON() ([]byte, error) {
+	if wt < 0 || int(wt) >= len(writingTones) {
+		return nil, fmt.Errorf("invalid writing tone value %d", wt)
+	}
 	return json.Marshal(wt.String())
 }
 
 // RephraseOptions represents the payload for the rephrase API call.
 // Text contains one or more strings to rephrase.
-// TargetLang is the target language code (optional).
+// TargetLang is the target language code (optional, case-insensitive).
 // WritingStyle specifies the desired style to adapt the text to audience and goals (optional).
 // WritingTone specifies the desired tone for the output text (optional).
 // Only one of WritingStyle or WritingTone can be set.
@@ -89,6 +106,29 @@ type RephraseOptions struct {
 	WritingTone  WritingTone  `json:"tone,omitempty"`
 }
 
+// Validate checks the options before they are sent: at least one text is required, no text may be blank,
+// WritingStyle and WritingTone must be valid enum values, and only one of them may be set.
+func (o RephraseOptions) Validate() error {
+	if len(o.Text) == 0 {
+		return errors.New("at least one text is required")
+	}
+	for i, text := range o.Text {
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("text at index %d is blank", i)
+		}
+	}
+	if o.WritingStyle < WritingStyleUnset || o.WritingStyle > WritingStylePreferSimple {
+		return fmt.Errorf("invalid writing style value %d", o.WritingStyle)
+	}
+	if o.WritingTone < WritingToneUnset || o.WritingTone > WritingTonePreferFriendly {
+		return fmt.Errorf("invalid writing tone value %d", o.WritingTone)
+	}
+	if o.WritingStyle != WritingStyleUnset && o.WritingTone != WritingToneUnset {
+		return errors.New("only one of WritingStyle or WritingTone can be set")
+	}
+	return nil
+}
+
 // Improvement contains a single rephrased result along with detected language info.
 type Improvement struct {
 	DetectedSourceLanguage string `json:"detected_source_language"`
@@ -114,17 +154,25 @@ func (c *Client) RephraseWithContext(ctx context.Context, text string) (*Improve
 	if err != nil {
 		return nil, err
 	}
-	if len(translations) == 0 {
-		return nil, errors.New("no improvements returned")
-	}
 	return translations[0], nil
 }
 
+// RephraseToString rephrases a single string using background context and returns only the rephrased text.
+// Use Rephrase instead if the detected source language is needed.
+func (c *Client) RephraseToString(text string) (string, error) {
+	improvement, err := c.RephraseWithContext(context.Background(), text)
+	if err != nil {
+		return "", err
+	}
+	return improvement.Text, nil
+}
+
 // RephraseWithOptions performs the rephrase request with complete options and returns improvements.
 func (c *Client) RephraseWithOptions(ctx context.Context, opts RephraseOptions) ([]*Improvement, error) {
-	if opts.WritingStyle != WritingStyle(0) && opts.WritingTone != WritingTone(0) {
-		return nil, errors.New("only one of WritingStyle or WritingTone can be set")
+	if err := opts.Validate(); err != nil {
+		return nil, err
 	}
+	opts.TargetLang = normalizeLangCode(opts.TargetLang)
 	data, err := json.Marshal(opts)
 	if err != nil {
 		return nil, err
@@ -134,8 +182,11 @@ func (c *Client) RephraseWithOptions(ctx context.Context, opts RephraseOptions)
 	if err != nil {
 		return nil, err
 	}
-	var response RephraseResponse
-	if err := c.doRequest(ctx, req, &response); err != nil {
+	response, err := doJSON[RephraseResponse](c, ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNotEmpty(response.Improvements, "improvements"); err != nil {
 		return nil, err
 	}
 	return response.Improvements, nil
@@ -2,7 +2,9 @@ package deepl
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -148,3 +150,162 @@ func TestRephraseWithOptions_ContextCancel(t *testing.T) {
 		t.Errorf("expected context.Canceled error, got %v", err)
 	}
 }
+
+func TestRephraseOptions_Validate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          RephraseOptions
+		expectedError string
+	}{
+		{"valid", RephraseOptions{Text: []string{"Some text"}, WritingTone: WritingToneFriendly}, ""},
+		{"no text", RephraseOptions{}, "at least one text"},
+		{"blank text", RephraseOptions{Text: []string{"Some text", "  "}}, "index 1 is blank"},
+		{"out-of-range style", RephraseOptions{Text: []string{"Some text"}, WritingStyle: WritingStyle(99)}, "invalid writing style"},
+		{"negative style", RephraseOptions{Text: []string{"Some text"}, WritingStyle: WritingStyle(-1)}, "invalid writing style"},
+		{"out-of-range tone", RephraseOptions{Text: []string{"Some text"}, WritingTone: WritingTone(42)}, "invalid writing tone"},
+		{"style and tone", RephraseOptions{Text: []string{"Some text"}, WritingStyle: WritingStyleCasual, WritingTone: WritingToneFriendly}, "only one of"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestRephraseWithOptions_OutOfRangeStyle(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Fatal("should not send request with an invalid writing style")
+		return nil
+	})
+
+	opts := RephraseOptions{
+		Text:         []string{"Some text"},
+		WritingStyle: WritingStyle(99),
+	}
+	_, err := client.RephraseWithOptions(context.Background(), opts)
+	if err == nil || !strings.Contains(err.Error(), "invalid writing style") {
+		t.Errorf("expected invalid writing style error, got %v", err)
+	}
+}
+
+func TestWritingStyleAndTone_String(t *testing.T) {
+	testCases := []struct {
+		got      string
+		expected string
+	}{
+		{WritingStyleUnset.String(), ""},
+		{WritingStyleAcademic.String(), "academic"},
+		{WritingStyleSimple.String(), "simple"},
+		{WritingStylePreferSimple.String(), "prefer_simple"},
+		{WritingToneUnset.String(), ""},
+		{WritingToneConfident.String(), "confident"},
+		{WritingToneFriendly.String(), "friendly"},
+		{WritingTonePreferFriendly.String(), "prefer_friendly"},
+	}
+
+	for _, tc := range testCases {
+		if tc.got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, tc.got)
+		}
+	}
+}
+
+func TestWritingStyleAndTone_OutOfRange(t *testing.T) {
+	for _, ws := range []WritingStyle{WritingStyle(10), WritingStyle(99), WritingStyle(-1)} {
+		if got := ws.String(); got != "" {
+			t.Errorf("expected empty string for WritingStyle(%d), got %q", ws, got)
+		}
+		if _, err := json.Marshal(ws); err == nil {
+			t.Errorf("expected marshal error for WritingStyle(%d)", ws)
+		}
+	}
+
+	for _, wt := range []WritingTone{WritingTone(10), WritingTone(99), WritingTone(-1)} {
+		if got := wt.String(); got != "" {
+			t.Errorf("expected empty string for WritingTone(%d), got %q", wt, got)
+		}
+		if _, err := json.Marshal(wt); err == nil {
+			t.Errorf("expected marshal error for WritingTone(%d)", wt)
+		}
+	}
+}
+
+func TestRephraseWithOptions_SendsStyle(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		if !strings.Contains(string(body), `"writing_style":"academic"`) {
+			t.Errorf("expected academic writing style in body, got %s", body)
+		}
+		return MockResponse(200, RephraseResponse{
+			Improvements: []*Improvement{{DetectedSourceLanguage: "EN", Text: "Rephrased"}},
+		})
+	})
+
+	_, err := client.RephraseWithOptions(context.Background(), RephraseOptions{
+		Text:         []string{"Some text"},
+		WritingStyle: WritingStyleAcademic,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRephraseWithOptions_UppercasesTargetLang(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		if !strings.Contains(string(body), `"target_lang":"EN-GB"`) {
+			t.Errorf("expected target language EN-GB in body, got %s", body)
+		}
+		return MockResponse(200, RephraseResponse{
+			Improvements: []*Improvement{{DetectedSourceLanguage: "EN", Text: "Rephrased"}},
+		})
+	})
+
+	_, err := client.RephraseWithOptions(context.Background(), RephraseOptions{
+		Text:       []string{"Some text"},
+		TargetLang: "en-gb",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRephraseToString(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, RephraseResponse{
+			Improvements: []*Improvement{{DetectedSourceLanguage: "EN", Text: "Rephrased text"}},
+		})
+	})
+
+	text, err := client.RephraseToString("Some text")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "Rephrased text" {
+		t.Errorf("expected %q, got %q", "Rephrased text", text)
+	}
+}
+
+func TestRephraseToString_NoImprovements(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, RephraseResponse{Improvements: []*Improvement{}})
+	})
+
+	text, err := client.RephraseToString("Some text")
+	if err == nil {
+		t.Fatal("expected error for empty improvements, got nil")
+	}
+	if text != "" {
+		t.Errorf("expected empty text on error, got %q", text)
+	}
+}
@@ -0,0 +1,112 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// streamFlushInterval is how long TranslateTextsStream waits for a batch to fill up before translating it anyway.
+const streamFlushInterval = 200 * time.Millisecond
+
+// TranslationResult is the outcome of translating a single text read by TranslateTextsStream.
+type TranslationResult struct {
+	Index       int          // Position of the text in the input channel, starting at 0
+	Text        string       // Text read from the input channel
+	Translation *Translation // Translation of Text, nil if Err is set
+	Err         error        // Error of the request that should have translated Text
+}
+
+// TranslateTextsStream reads texts from a channel, translates them into the target language, and emits a result
+// per text in the order they were read. Texts are sent in batches of up to 50; a batch is sent as soon as it is
+// full or, when texts arrive slowly, at the latest 200ms after its first text was read. Once texts is closed, the
+// remaining batch is translated and the result channel is closed.
+// Only one request is in flight at a time, and no further texts are read while a batch is translated or its
+// results wait to be received, so a slow consumer throttles the producer.
+// A failed request does not stop the stream: its texts are emitted with Err set. When ctx is cancelled, the
+// result channel is closed without translating the pending texts.
+func (c *Client) TranslateTextsStream(ctx context.Context, texts <-chan string, targetLang string) (<-chan TranslationResult, error) {
+	if texts == nil {
+		return nil, errors.New("texts channel must not be nil")
+	}
+	if strings.TrimSpace(targetLang) == "" {
+		return nil, errors.New("target language is required")
+	}
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+
+	results := make(chan TranslationResult)
+	go c.streamTranslations(ctx, texts, targetLang, results)
+	return results, nil
+}
+
+// streamTranslations implements TranslateTextsStream, closing results when texts is closed or ctx is cancelled.
+func (c *Client) streamTranslations(ctx context.Context, texts <-chan string, targetLang string, results chan<- TranslationResult) {
+	defer close(results)
+
+	var (
+		batch []string
+		next  int
+		timer *time.Timer
+		flush <-chan time.Time
+	)
+	// send translates the batch and emits its results, reporting false if ctx was cancelled meanwhile.
+	send := func() bool {
+		if timer != nil {
+			timer.Stop()
+			timer, flush = nil, nil
+		}
+		translations, err := c.TranslateTextWithOptions(ctx, TranslateTextOptions{Text: batch, TargetLang: targetLang})
+		if ctx.Err() != nil {
+			return false
+		}
+		if err == nil && len(translations) != len(batch) {
+			err = errors.New("number of translations does not match number of texts")
+		}
+		for i, text := range batch {
+			result := TranslationResult{Index: next, Text: text, Err: err}
+			if err == nil {
+				result.Translation = translations[i]
+			}
+			select {
+			case results <- result:
+				next++
+			case <-ctx.Done():
+				return false
+			}
+		}
+		batch = nil
+		return true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case text, ok := <-texts:
+			if !ok {
+				if len(batch) > 0 {
+					send()
+				}
+				return
+			}
+			batch = append(batch, text)
+			if timer == nil {
+				timer = time.NewTimer(streamFlushInterval)
+				flush = timer.C
+			}
+			if len(batch) == maxTextsPerRequest && !send() {
+				return
+			}
+		case <-flush:
+			if !send() {
+				return
+			}
+		}
+	}
+}
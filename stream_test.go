@@ -0,0 +1,137 @@
+package deepl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTranslateTextsStream(t *testing.T) {
+	var requests [][]string
+	client := NewTestClient(upperCaseTranslations(t, &requests))
+
+	texts := make(chan string)
+	go func() {
+		defer close(texts)
+		for i := 0; i < 120; i++ {
+			texts <- fmt.Sprintf("text %d", i)
+		}
+	}()
+
+	results, err := client.TranslateTextsStream(context.Background(), texts, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	count := 0
+	for result := range results {
+		expected := fmt.Sprintf("text %d", count)
+		if result.Err != nil {
+			t.Fatalf("unexpected error for %q: %v", result.Text, result.Err)
+		}
+		if result.Index != count || result.Text != expected || result.Translation.Text != strings.ToUpper(expected) {
+			t.Errorf("unexpected result %d: %+v", count, result)
+		}
+		count++
+	}
+	if count != 120 {
+		t.Errorf("expected 120 results, got %d", count)
+	}
+
+	var sizes []int
+	for _, texts := range requests {
+		sizes = append(sizes, len(texts))
+	}
+	if !reflect.DeepEqual(sizes, []int{50, 50, 20}) {
+		t.Errorf("expected requests of 50, 50 and 20 texts, got %v", sizes)
+	}
+}
+
+func TestTranslateTextsStream_FlushesAfterTimeout(t *testing.T) {
+	var requests [][]string
+	client := NewTestClient(upperCaseTranslations(t, &requests))
+
+	texts := make(chan string)
+	defer close(texts)
+	results, err := client.TranslateTextsStream(context.Background(), texts, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	texts <- "Hello"
+	select {
+	case result := <-results:
+		if result.Err != nil || result.Translation.Text != "HELLO" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	case <-time.After(5 * streamFlushInterval):
+		t.Fatal("expected the incomplete batch to be flushed")
+	}
+}
+
+func TestTranslateTextsStream_RequestError(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(400, map[string]string{"message": "Bad request"})
+	})
+
+	texts := make(chan string, 2)
+	texts <- "Hello"
+	texts <- "World"
+	close(texts)
+
+	results, err := client.TranslateTextsStream(context.Background(), texts, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	count := 0
+	for result := range results {
+		if result.Err == nil || result.Translation != nil {
+			t.Errorf("expected an error result, got %+v", result)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("expected 2 results, got %d", count)
+	}
+}
+
+func TestTranslateTextsStream_ContextCancelled(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Error("expected no request to be sent")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	texts := make(chan string)
+	results, err := client.TranslateTextsStream(ctx, texts, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	texts <- "Hello"
+	cancel()
+	select {
+	case result, ok := <-results:
+		if ok {
+			t.Errorf("expected the result channel to be closed, got %+v", result)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the result channel to be closed after cancellation")
+	}
+}
+
+func TestTranslateTextsStream_InvalidArguments(t *testing.T) {
+	client := NewTestClient(nil)
+
+	if _, err := client.TranslateTextsStream(context.Background(), nil, "DE"); err == nil {
+		t.Error("expected error for nil channel")
+	}
+	if _, err := client.TranslateTextsStream(context.Background(), make(chan string), " "); err == nil {
+		t.Error("expected error for missing target language")
+	}
+}
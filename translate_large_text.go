@@ -0,0 +1,187 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxLargeTextChunkSize is the maximum number of bytes of text sent per request by TranslateLargeText.
+// DeepL limits the total request size to 128 KiB, so this leaves room for the remaining request parameters.
+const maxLargeTextChunkSize = 100 * 1024
+
+// TranslateLargeText translates a plain text that may be too large for a single request.
+// The text is split into chunks on paragraph and sentence boundaries: every paragraph starts a new chunk,
+// and sentences of a paragraph are grouped into chunks below the request size limit. Consecutive chunks are
+// sent together, up to 50 texts and the size limit per request, with the given options, and the translations
+// are reassembled with the original whitespace and paragraph breaks between them. Only sentences longer than
+// the limit are split further, at word boundaries.
+//
+// The splitting follows opts.SplitSentenceMode: with SplitSentenceModeNoNewlines, single line breaks do not end
+// a sentence, and with SplitSentenceModeOff, a paragraph is only split if it exceeds the limit. opts may be
+// nil; its Text and TargetLang fields are ignored.
+func (c *Client) TranslateLargeText(ctx context.Context, text, targetLang string, opts *TranslateTextOptions) (string, error) {
+	return c.translateLargeText(ctx, text, targetLang, opts, maxLargeTextChunkSize)
+}
+
+// translateLargeText implements TranslateLargeText with a configurable chunk size, which also limits the
+// total size of the texts sent per request.
+func (c *Client) translateLargeText(ctx context.Context, text, targetLang string, opts *TranslateTextOptions, chunkSize int) (string, error) {
+	var base TranslateTextOptions
+	if opts != nil {
+		base = *opts
+	}
+	base.TargetLang = targetLang
+	chunks, gaps := splitLargeText(text, chunkSize, base.splitSentences())
+
+	translated := make([]string, 0, len(chunks))
+	for start := 0; start < len(chunks); {
+		end, size := start, 0
+		for end < len(chunks) && end-start < maxTextsPerRequest && (end == start || size+len(chunks[end]) <= chunkSize) {
+			size += len(chunks[end])
+			end++
+		}
+
+		batchOpts := base
+		batchOpts.Text = chunks[start:end]
+		translations, err := c.TranslateTextWithOptions(ctx, batchOpts)
+		if err != nil {
+			return "", err
+		}
+		if len(translations) != end-start {
+			return "", errors.New("number of translations does not match number of texts")
+		}
+		for _, translation := range translations {
+			translated = append(translated, translation.Text)
+		}
+		start = end
+	}
+
+	var b strings.Builder
+	b.WriteString(gaps[0])
+	for i, chunk := range translated {
+		b.WriteString(chunk)
+		b.WriteString(gaps[i+1])
+	}
+	return b.String(), nil
+}
+
+// textUnit is a sentence (or a line without a sentence terminator) followed by the whitespace after it.
+type textUnit struct {
+	content string
+	space   string
+}
+
+// splitLargeText splits text into chunks of at most chunkSize bytes without breaking sentences where possible.
+// Every paragraph starts a new chunk. Sentences are recognized according to mode as by splitTextUnits.
+// It returns the chunks without surrounding whitespace and the whitespace gaps around them, so that
+// gaps[0] + chunks[0] + gaps[1] + ... + chunks[n-1] + gaps[n] reproduces the original text.
+func splitLargeText(text string, chunkSize int, mode SplitSentenceMode) (chunks []string, gaps []string) {
+	body := strings.TrimLeftFunc(text, unicode.IsSpace)
+	gaps = append(gaps, text[:len(text)-len(body)])
+	if body == "" {
+		return nil, gaps
+	}
+
+	var current strings.Builder
+	var pendingSpace string
+	for _, unit := range splitTextUnits(body, mode) {
+		for _, piece := range splitOversizedUnit(unit, chunkSize) {
+			if current.Len() > 0 && (isParagraphBreak(pendingSpace) || current.Len()+len(pendingSpace)+len(piece.content) > chunkSize) {
+				chunks = append(chunks, current.String())
+				gaps = append(gaps, pendingSpace)
+				current.Reset()
+				pendingSpace = ""
+			}
+			current.WriteString(pendingSpace)
+			current.WriteString(piece.content)
+			pendingSpace = piece.space
+		}
+	}
+	chunks = append(chunks, current.String())
+	gaps = append(gaps, pendingSpace)
+	return chunks, gaps
+}
+
+// splitTextUnits splits text, which must not start with whitespace, into sentences and lines.
+// A unit ends at a paragraph break and, depending on mode, after a sentence terminator (optionally followed
+// by closing quotes or brackets) that is followed by whitespace, or at any line break. Like DeepL, mode
+// SplitSentenceModeNoNewlines ignores single line breaks, and SplitSentenceModeOff ends units at paragraph
+// breaks only.
+func splitTextUnits(text string, mode SplitSentenceMode) []textUnit {
+	var units []textUnit
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+
+		spaceEnd := i
+		for spaceEnd < len(text) {
+			sr, ssize := utf8.DecodeRuneInString(text[spaceEnd:])
+			if !unicode.IsSpace(sr) {
+				break
+			}
+			spaceEnd += ssize
+		}
+
+		space := text[i:spaceEnd]
+		lineBreak := strings.Contains(space, "\n") && mode != SplitSentenceModeNoNewlines && mode != SplitSentenceModeOff
+		sentenceEnd := mode != SplitSentenceModeOff && endsSentence(text[start:i])
+		if isParagraphBreak(space) || lineBreak || sentenceEnd || spaceEnd == len(text) {
+			units = append(units, textUnit{content: text[start:i], space: space})
+			start = spaceEnd
+		}
+		i = spaceEnd
+	}
+	if start < len(text) {
+		units = append(units, textUnit{content: text[start:]})
+	}
+	return units
+}
+
+// isParagraphBreak reports whether the whitespace between two units separates paragraphs, i.e. contains a blank line.
+func isParagraphBreak(space string) bool {
+	return strings.Count(space, "\n") >= 2
+}
+
+// endsSentence reports whether s ends with a sentence terminator, ignoring trailing closing quotes and brackets.
+func endsSentence(s string) bool {
+	s = strings.TrimRight(s, "\"')]}»”’")
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return strings.ContainsRune(".!?…。！？", r)
+}
+
+// splitOversizedUnit splits a unit longer than chunkSize at word boundaries, falling back to rune boundaries
+// for words that are longer than chunkSize themselves. Units within the limit are returned unchanged.
+func splitOversizedUnit(unit textUnit, chunkSize int) []textUnit {
+	if len(unit.content) <= chunkSize {
+		return []textUnit{unit}
+	}
+
+	var pieces []textUnit
+	rest := unit.content
+	for len(rest) > chunkSize {
+		cut := strings.LastIndexFunc(rest[:chunkSize+1], unicode.IsSpace)
+		if cut <= 0 {
+			// No whitespace to split at; cut at the last rune boundary within the limit.
+			cut = chunkSize
+			for cut > 0 && !utf8.RuneStart(rest[cut]) {
+				cut--
+			}
+			pieces = append(pieces, textUnit{content: rest[:cut]})
+			rest = rest[cut:]
+			continue
+		}
+		word := strings.TrimRightFunc(rest[:cut], unicode.IsSpace)
+		next := strings.TrimLeftFunc(rest[cut:], unicode.IsSpace)
+		pieces = append(pieces, textUnit{content: word, space: rest[len(word) : len(rest)-len(next)]})
+		rest = next
+	}
+	pieces = append(pieces, textUnit{content: rest, space: unit.space})
+	return pieces
+}
@@ -0,0 +1,150 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// upperCaseTranslations returns a mock translating every text of a request into upper case and records the
+// texts of each request.
+func upperCaseTranslations(t *testing.T, requests *[][]string) RoundTripFunc {
+	return func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		var requestData TranslateTextOptions
+		if err := json.Unmarshal(body, &requestData); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if requestData.TargetLang != "DE" {
+			t.Errorf("expected target language DE, got %q", requestData.TargetLang)
+		}
+		*requests = append(*requests, requestData.Text)
+
+		var translations []*Translation
+		for _, text := range requestData.Text {
+			translations = append(translations, &Translation{Text: strings.ToUpper(text)})
+		}
+		return MockResponse(200, TranslationsResponse{Translations: translations})
+	}
+}
+
+func TestTranslateLargeText_MultiParagraph(t *testing.T) {
+	input := "  First sentence here. Second sentence follows!\n\n" +
+		"A new paragraph starts. Is it translated?\n" +
+		"A line without a terminator\n\n" +
+		"The last paragraph ends here.\n"
+
+	var requests [][]string
+	client := NewTestClient(upperCaseTranslations(t, &requests))
+
+	result, err := client.translateLargeText(context.Background(), input, "DE", &TranslateTextOptions{FormalityLevel: FormalityMore}, 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != strings.ToUpper(input) {
+		t.Errorf("expected reassembled text %q, got %q", strings.ToUpper(input), result)
+	}
+
+	var sent []string
+	for _, texts := range requests {
+		size := 0
+		for _, text := range texts {
+			size += len(text)
+		}
+		if size > 60 {
+			t.Errorf("request exceeds the size limit: %q", texts)
+		}
+		sent = append(sent, texts...)
+	}
+	if len(requests) >= len(sent) {
+		t.Errorf("expected chunks to be batched, got %d requests for %d chunks", len(requests), len(sent))
+	}
+
+	expected := []string{
+		"First sentence here. Second sentence follows!",
+		"A new paragraph starts. Is it translated?",
+		"A line without a terminator",
+		"The last paragraph ends here.",
+	}
+	if !reflect.DeepEqual(sent, expected) {
+		t.Errorf("expected chunks %q, got %q", expected, sent)
+	}
+}
+
+func TestTranslateLargeText_BatchesUpTo50Texts(t *testing.T) {
+	paragraphs := make([]string, 120)
+	for i := range paragraphs {
+		paragraphs[i] = fmt.Sprintf("Paragraph %d.", i)
+	}
+	input := strings.Join(paragraphs, "\n\n")
+
+	var requests [][]string
+	client := NewTestClient(upperCaseTranslations(t, &requests))
+
+	result, err := client.TranslateLargeText(context.Background(), input, "DE", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != strings.ToUpper(input) {
+		t.Errorf("expected reassembled text %q, got %q", strings.ToUpper(input), result)
+	}
+
+	var sizes []int
+	for _, texts := range requests {
+		sizes = append(sizes, len(texts))
+	}
+	if !reflect.DeepEqual(sizes, []int{50, 50, 20}) {
+		t.Errorf("expected requests of 50, 50 and 20 texts, got %v", sizes)
+	}
+}
+
+func TestSplitLargeText(t *testing.T) {
+	testCases := []struct {
+		name      string
+		text      string
+		chunkSize int
+		mode      SplitSentenceMode
+		expected  []string
+	}{
+		{"fits in one chunk", "One. Two.", 100, SplitSentenceModeUnset, []string{"One. Two."}},
+		{"splits between sentences", "One sentence. Another one.", 15, SplitSentenceModeUnset, []string{"One sentence.", "Another one."}},
+		{"splits at paragraph", "Para one\n\nPara two", 10, SplitSentenceModeUnset, []string{"Para one", "Para two"}},
+		{"always splits paragraphs", "One.\n\nTwo.", 100, SplitSentenceModeUnset, []string{"One.", "Two."}},
+		{"splits at line break", "Line one\nLine two", 10, SplitSentenceModeOn, []string{"Line one", "Line two"}},
+		{"line break ends sentence", "Line one\nline two three", 15, SplitSentenceModeUnset, []string{"Line one", "line two three"}},
+		{"no newlines keeps lines", "Line one\nline two three", 15, SplitSentenceModeNoNewlines, []string{"Line one\nline", "two three"}},
+		{"no newlines splits sentences", "Line one.\nLine two.", 10, SplitSentenceModeNoNewlines, []string{"Line one.", "Line two."}},
+		{"off keeps sentences", "One. Two. Three.", 10, SplitSentenceModeOff, []string{"One. Two.", "Three."}},
+		{"off splits paragraphs", "One. Two.\n\nThree.", 100, SplitSentenceModeOff, []string{"One. Two.", "Three."}},
+		{"splits long sentence at words", "a very long sentence without end", 12, SplitSentenceModeUnset, []string{"a very long", "sentence", "without end"}},
+		{"splits long word at runes", "äääää", 4, SplitSentenceModeUnset, []string{"ää", "ää", "ä"}},
+		{"whitespace only", " \n ", 10, SplitSentenceModeUnset, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chunks, gaps := splitLargeText(tc.text, tc.chunkSize, tc.mode)
+			if strings.Join(chunks, "|") != strings.Join(tc.expected, "|") {
+				t.Errorf("expected chunks %q, got %q", tc.expected, chunks)
+			}
+			if len(gaps) != len(chunks)+1 {
+				t.Fatalf("expected %d gaps, got %d", len(chunks)+1, len(gaps))
+			}
+
+			var b strings.Builder
+			b.WriteString(gaps[0])
+			for i, chunk := range chunks {
+				b.WriteString(chunk)
+				b.WriteString(gaps[i+1])
+			}
+			if b.String() != tc.text {
+				t.Errorf("expected chunks and gaps to reproduce %q, got %q", tc.text, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package deepl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParseTranslateOptions builds TranslateTextOptions from URL query or form values using the DeepL parameter
+// names, e.g. "text", "target_lang", "formality" or "preserve_formatting". This makes it easy to forward the
+// parameters of an incoming HTTP request. Boolean parameters accept the values understood by strconv.ParseBool,
+// and tag lists may be given as repeated or comma-separated values. Unknown parameters are ignored.
+func ParseTranslateOptions(v url.Values) (TranslateTextOptions, error) {
+	opts := TranslateTextOptions{
+		Text:        v["text"],
+		SourceLang:  v.Get("source_lang"),
+		TargetLang:  v.Get("target_lang"),
+		Context:     v.Get("context"),
+		GlossaryID:  v.Get("glossary_id"),
+		TagHandling: v.Get("tag_handling"),
+	}
+	if opts.TargetLang == "" {
+		return TranslateTextOptions{}, errors.New("missing required parameter target_lang")
+	}
+
+	split, err := parseSplitSentenceMode(v.Get("split_sentences"))
+	if err != nil {
+		return TranslateTextOptions{}, fmt.Errorf("invalid split_sentences %q: must be \"0\", \"1\" or \"nonewlines\"", v.Get("split_sentences"))
+	}
+	opts.SplitSentenceMode = split
+
+	modelType, err := parseModelType(v.Get("model_type"))
+	if err != nil {
+		return TranslateTextOptions{}, fmt.Errorf("invalid model_type %q", v.Get("model_type"))
+	}
+	opts.Model = modelType
+
+	switch opts.TagHandling {
+	case "", "xml", "html":
+	default:
+		return TranslateTextOptions{}, fmt.Errorf("invalid tag_handling %q: must be \"xml\" or \"html\"", opts.TagHandling)
+	}
+
+	formality, err := parseFormality(v.Get("formality"))
+	if err != nil {
+		return TranslateTextOptions{}, err
+	}
+	opts.FormalityLevel = formality
+
+	boolParams := []struct {
+		name  string
+		field **bool
+	}{
+		{"show_billed_characters", &opts.ShowBilledCharacters},
+		{"preserve_formatting", &opts.PreserveFormatting},
+		{"outline_detection", &opts.OutlineDetection},
+	}
+	for _, param := range boolParams {
+		raw := v.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return TranslateTextOptions{}, fmt.Errorf("invalid %s %q: must be a boolean", param.name, raw)
+		}
+		*param.field = BoolPtr(b)
+	}
+
+	opts.NonSplittingTags = splitListParam(v["non_splitting_tags"])
+	opts.SplittingTags = splitListParam(v["splitting_tags"])
+	opts.IgnoreTags = splitListParam(v["ignore_tags"])
+
+	return opts, nil
+}
+
+// splitListParam flattens repeated and comma-separated parameter values into a single list without empty entries.
+func splitListParam(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// Normalize validates the options and returns a cleaned copy that shares no slices or pointers with o.
+// Language codes and identifiers are trimmed and empty tag entries are dropped. All problems found are
+// returned together, including options that conflict with each other such as tag lists without TagHandling.
+func (o TranslateTextOptions) Normalize() (TranslateTextOptions, error) {
+	n := o
+	n.Text = append([]string(nil), o.Text...)
+	n.SourceLang = strings.TrimSpace(o.SourceLang)
+	n.TargetLang = strings.TrimSpace(o.TargetLang)
+	n.GlossaryID = strings.TrimSpace(o.GlossaryID)
+	n.ShowBilledCharacters = copyBoolPtr(o.ShowBilledCharacters)
+	n.PreserveFormatting = copyBoolPtr(o.PreserveFormatting)
+	n.OutlineDetection = copyBoolPtr(o.OutlineDetection)
+	n.NonSplittingTags = splitListParam(o.NonSplittingTags)
+	n.SplittingTags = splitListParam(o.SplittingTags)
+	n.IgnoreTags = splitListParam(o.IgnoreTags)
+
+	var errs []error
+	if len(n.Text) == 0 {
+		errs = append(errs, errors.New("at least one text is required"))
+	}
+	if n.TargetLang == "" {
+		errs = append(errs, errors.New("target language is required"))
+	}
+	if _, err := n.withEnumValues(); err != nil {
+		errs = append(errs, err)
+	}
+	switch n.TagHandling {
+	case "xml", "html":
+	case "":
+		if n.OutlineDetection != nil || len(n.NonSplittingTags) > 0 || len(n.SplittingTags) > 0 || len(n.IgnoreTags) > 0 {
+			errs = append(errs, errors.New("outline detection and tag lists require TagHandling to be set"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid tag handling %q", n.TagHandling))
+	}
+	if n.GlossaryID != "" && n.SourceLang == "" {
+		errs = append(errs, errors.New("a glossary requires the source language to be set"))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return TranslateTextOptions{}, err
+	}
+	return n, nil
+}
+
+// copyBoolPtr returns a new pointer to the value of b, or nil if b is nil.
+func copyBoolPtr(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	return BoolPtr(*b)
+}
+
+// translateDefaults holds the client defaults of the boolean TranslateTextOptions. Nil values leave the
+// choice to DeepL.
+type translateDefaults struct {
+	ShowBilledCharacters *bool
+	PreserveFormatting   *bool
+	OutlineDetection     *bool
+}
+
+// WithShowBilledCharacters returns an Option that sets the default of TranslateTextOptions.ShowBilledCharacters
+// for all translations of the client. A value set on the options of a request takes precedence.
+func WithShowBilledCharacters(show bool) Option {
+	return func(c *Client) {
+		c.translateDefaults.ShowBilledCharacters = BoolPtr(show)
+	}
+}
+
+// WithPreserveFormatting returns an Option that sets the default of TranslateTextOptions.PreserveFormatting
+// for all translations of the client. A value set on the options of a request takes precedence.
+func WithPreserveFormatting(preserve bool) Option {
+	return func(c *Client) {
+		c.translateDefaults.PreserveFormatting = BoolPtr(preserve)
+	}
+}
+
+// WithOutlineDetection returns an Option that sets the default of TranslateTextOptions.OutlineDetection
+// for all translations of the client. A value set on the options of a request takes precedence. As DeepL
+// only detects outlines in XML, the default is applied to requests with TagHandling set only.
+func WithOutlineDetection(detect bool) Option {
+	return func(c *Client) {
+		c.translateDefaults.OutlineDetection = BoolPtr(detect)
+	}
+}
+
+// applyTranslateDefaults returns opts with every unset boolean option set to the client default, if any.
+func (c *Client) applyTranslateDefaults(opts TranslateTextOptions) TranslateTextOptions {
+	if opts.ShowBilledCharacters == nil {
+		opts.ShowBilledCharacters = copyBoolPtr(c.translateDefaults.ShowBilledCharacters)
+	}
+	if opts.PreserveFormatting == nil {
+		opts.PreserveFormatting = copyBoolPtr(c.translateDefaults.PreserveFormatting)
+	}
+	if opts.OutlineDetection == nil && opts.TagHandling != "" {
+		opts.OutlineDetection = copyBoolPtr(c.translateDefaults.OutlineDetection)
+	}
+	return opts
+}
@@ -0,0 +1,206 @@
+package deepl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTranslateOptions(t *testing.T) {
+	query := "text=Hello&text=World&target_lang=DE&source_lang=EN&formality=prefer_less" +
+		"&tag_handling=xml&preserve_formatting=true&outline_detection=0&split_sentences=nonewlines" +
+		"&ignore_tags=x,code&ignore_tags=pre&context=greeting&glossary_id=abc&unknown=ignored"
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	opts, err := ParseTranslateOptions(values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := TranslateTextOptions{
+		Text:               []string{"Hello", "World"},
+		SourceLang:         "EN",
+		TargetLang:         "DE",
+		Context:            "greeting",
+		SplitSentenceMode:  SplitSentenceModeNoNewlines,
+		PreserveFormatting: BoolPtr(true),
+		FormalityLevel:     FormalityPreferLess,
+		GlossaryID:         "abc",
+		TagHandling:        "xml",
+		OutlineDetection:   BoolPtr(false),
+		IgnoreTags:         []string{"x", "code", "pre"},
+	}
+	if !reflect.DeepEqual(opts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, opts)
+	}
+	if opts.ShowBilledCharacters != nil {
+		t.Error("expected absent boolean parameter to stay nil")
+	}
+}
+
+func TestParseTranslateOptions_Invalid(t *testing.T) {
+	testCases := []struct {
+		query         string
+		expectedError string
+	}{
+		{"text=Hello", "target_lang"},
+		{"target_lang=DE&formality=polite", "formality"},
+		{"target_lang=DE&tag_handling=markdown", "tag_handling"},
+		{"target_lang=DE&split_sentences=2", "split_sentences"},
+		{"target_lang=DE&model_type=fast", "model_type"},
+		{"target_lang=DE&preserve_formatting=maybe", "preserve_formatting"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			values, _ := url.ParseQuery(tc.query)
+			_, err := ParseTranslateOptions(values)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("expected error mentioning %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestTrueFalse(t *testing.T) {
+	if v := True(); v == nil || !*v {
+		t.Error("expected True() to point to true")
+	}
+	if v := False(); v == nil || *v {
+		t.Error("expected False() to point to false")
+	}
+	if True() == True() {
+		t.Error("expected True() to return a new pointer on every call")
+	}
+}
+
+func TestTranslateTextOptions_Normalize(t *testing.T) {
+	original := TranslateTextOptions{
+		Text:             []string{"Hello"},
+		SourceLang:       " EN ",
+		TargetLang:       "DE\n",
+		TagHandling:      "xml",
+		OutlineDetection: False(),
+		IgnoreTags:       []string{"x", " ", "code"},
+	}
+
+	normalized, err := original.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if normalized.SourceLang != "EN" || normalized.TargetLang != "DE" {
+		t.Errorf("expected trimmed language codes, got %q and %q", normalized.SourceLang, normalized.TargetLang)
+	}
+	if !reflect.DeepEqual(normalized.IgnoreTags, []string{"x", "code"}) {
+		t.Errorf("expected empty tags to be dropped, got %q", normalized.IgnoreTags)
+	}
+	if normalized.OutlineDetection == original.OutlineDetection || *normalized.OutlineDetection {
+		t.Error("expected a copied pointer with the same value")
+	}
+
+	normalized.Text[0] = "Changed"
+	if original.Text[0] != "Hello" {
+		t.Error("expected the normalized copy not to share the text slice")
+	}
+}
+
+func TestTranslateTextOptions_NormalizeConflicts(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          TranslateTextOptions
+		expectedError string
+	}{
+		{"missing text", TranslateTextOptions{TargetLang: "DE"}, "at least one text"},
+		{"missing target", TranslateTextOptions{Text: []string{"Hello"}}, "target language"},
+		{"tags without tag handling", TranslateTextOptions{Text: []string{"Hello"}, TargetLang: "DE", IgnoreTags: []string{"x"}}, "require TagHandling"},
+		{"outline detection without tag handling", TranslateTextOptions{Text: []string{"Hello"}, TargetLang: "DE", OutlineDetection: True()}, "require TagHandling"},
+		{"glossary without source", TranslateTextOptions{Text: []string{"Hello"}, TargetLang: "DE", GlossaryID: "abc"}, "source language"},
+		{"invalid formality", TranslateTextOptions{Text: []string{"Hello"}, TargetLang: "DE", FormalityLevel: Formality(42)}, "formality"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.opts.Normalize()
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("expected error mentioning %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+
+	_, err := TranslateTextOptions{}.Normalize()
+	if err == nil || !strings.Contains(err.Error(), "at least one text") || !strings.Contains(err.Error(), "target language") {
+		t.Errorf("expected all problems to be reported, got %v", err)
+	}
+}
+
+func TestTranslateDefaults_Precedence(t *testing.T) {
+	testCases := []struct {
+		name          string
+		clientDefault *bool
+		request       *bool
+		expected      string
+	}{
+		{"API default", nil, nil, ""},
+		{"client default", BoolPtr(true), nil, `"preserve_formatting":true`},
+		{"request overrides client default", BoolPtr(true), BoolPtr(false), `"preserve_formatting":false`},
+		{"request without client default", nil, BoolPtr(true), `"preserve_formatting":true`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body string
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				data, _ := io.ReadAll(req.Body)
+				body = string(data)
+				return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+			})
+			if tc.clientDefault != nil {
+				WithPreserveFormatting(*tc.clientDefault)(client)
+			}
+
+			opts := TranslateTextOptions{Text: []string{"Hello"}, TargetLang: "DE", PreserveFormatting: tc.request}
+			if _, err := client.TranslateTextWithOptions(context.Background(), opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.expected == "" {
+				if strings.Contains(body, "preserve_formatting") {
+					t.Errorf("expected preserve_formatting to be omitted, got %s", body)
+				}
+			} else if !strings.Contains(body, tc.expected) {
+				t.Errorf("expected %s in body, got %s", tc.expected, body)
+			}
+		})
+	}
+}
+
+func TestTranslateDefaults_AllOptions(t *testing.T) {
+	client := NewTestClient(nil)
+	WithShowBilledCharacters(true)(client)
+	WithPreserveFormatting(true)(client)
+	WithOutlineDetection(false)(client)
+
+	opts := client.applyTranslateDefaults(TranslateTextOptions{TagHandling: "xml", ShowBilledCharacters: BoolPtr(false)})
+	if opts.ShowBilledCharacters == nil || *opts.ShowBilledCharacters {
+		t.Error("expected the request value of ShowBilledCharacters to take precedence")
+	}
+	if opts.PreserveFormatting == nil || !*opts.PreserveFormatting {
+		t.Error("expected PreserveFormatting to default to true")
+	}
+	if opts.OutlineDetection == nil || *opts.OutlineDetection {
+		t.Error("expected OutlineDetection to default to false")
+	}
+
+	opts = client.applyTranslateDefaults(TranslateTextOptions{})
+	if opts.OutlineDetection != nil {
+		t.Error("expected OutlineDetection not to be set without TagHandling")
+	}
+}
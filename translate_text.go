@@ -7,25 +7,233 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Formality sets whether the translated text should lean towards formal or informal language.
+// The zero value FormalityUnset omits the parameter so that DeepL applies its default, whereas
+// FormalityDefault explicitly sends "default". The `prefer_` prefix falls back to the default
+// formality if the target language does not support formality.
+type Formality int8
+
+const (
+	FormalityUnset Formality = iota
+	FormalityDefault
+	FormalityMore
+	FormalityLess
+	FormalityPreferMore
+	FormalityPreferLess
 )
 
+// formalities lists the API values of the Formality enum, indexed by their enum value.
+var formalities = [...]string{"", "default", "more", "less", "prefer_more", "prefer_less"}
+
+// String returns the string representation of the Formality enum, or an empty string for unknown values.
+func (f Formality) String() string {
+	if f < 0 || int(f) >= len(formalities) {
+		return ""
+	}
+	return formalities[f]
+}
+
+// MarshalJSON implements the json.Marshaler interface for Formality.
+// It serializes the Formality value as its string representation.
+func (f Formality) MarshalJSON() ([]byte, error) {
+	if f < 0 || int(f) >= len(formalities) {
+		return nil, fmt.Errorf("invalid formality value %d", f)
+	}
+	return json.Marshal(f.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Formality.
+func (f *Formality) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := parseFormality(s)
+	if err != nil {
+		return err
+	}
+	*f = parsed
+	return nil
+}
+
+// parseFormality returns the Formality for its API value. An empty string yields FormalityUnset.
+func parseFormality(s string) (Formality, error) {
+	for i, name := range formalities {
+		if name == s {
+			return Formality(i), nil
+		}
+	}
+	return FormalityUnset, fmt.Errorf("invalid formality %q", s)
+}
+
+// SplitSentenceMode sets whether DeepL splits the input text into sentences before translating it.
+// The zero value SplitSentenceModeUnset omits the parameter so that DeepL applies its default.
+// SplitSentenceModeNoNewlines splits on punctuation only, ignoring newlines.
+type SplitSentenceMode int8
+
+const (
+	SplitSentenceModeUnset SplitSentenceMode = iota
+	SplitSentenceModeOff
+	SplitSentenceModeOn
+	SplitSentenceModeNoNewlines
+)
+
+// splitSentenceModes lists the API values of the SplitSentenceMode enum, indexed by their enum value.
+var splitSentenceModes = [...]string{"", "0", "1", "nonewlines"}
+
+// String returns the string representation of the SplitSentenceMode enum, or an empty string for unknown values.
+func (m SplitSentenceMode) String() string {
+	if m < 0 || int(m) >= len(splitSentenceModes) {
+		return ""
+	}
+	return splitSentenceModes[m]
+}
+
+// MarshalJSON implements the json.Marshaler interface for SplitSentenceMode.
+// It serializes the SplitSentenceMode value as its string representation.
+func (m SplitSentenceMode) MarshalJSON() ([]byte, error) {
+	if m < 0 || int(m) >= len(splitSentenceModes) {
+		return nil, fmt.Errorf("invalid split sentences mode %d", m)
+	}
+	return json.Marshal(m.String())
+}
+
+// parseSplitSentenceMode returns the SplitSentenceMode for its API value. An empty string yields SplitSentenceModeUnset.
+func parseSplitSentenceMode(s string) (SplitSentenceMode, error) {
+	for i, name := range splitSentenceModes {
+		if name == s {
+			return SplitSentenceMode(i), nil
+		}
+	}
+	return SplitSentenceModeUnset, fmt.Errorf("invalid split sentences mode %q", s)
+}
+
+// ModelType selects the model DeepL uses for a translation. The zero value ModelTypeUnset omits the parameter
+// so that DeepL applies its default. The `prefer_` prefix falls back to the latency-optimized model if the
+// language pair does not support the quality-optimized one.
+type ModelType int8
+
+const (
+	ModelTypeUnset ModelType = iota
+	ModelTypeLatencyOptimized
+	ModelTypeQualityOptimized
+	ModelTypePreferQualityOptimized
+)
+
+// modelTypes lists the API values of the ModelType enum, indexed by their enum value.
+var modelTypes = [...]string{"", "latency_optimized", "quality_optimized", "prefer_quality_optimized"}
+
+// String returns the string representation of the ModelType enum, or an empty string for unknown values.
+func (m ModelType) String() string {
+	if m < 0 || int(m) >= len(modelTypes) {
+		return ""
+	}
+	return modelTypes[m]
+}
+
+// MarshalJSON implements the json.Marshaler interface for ModelType.
+// It serializes the ModelType value as its string representation.
+func (m ModelType) MarshalJSON() ([]byte, error) {
+	if m < 0 || int(m) >= len(modelTypes) {
+		return nil, fmt.Errorf("invalid model type value %d", m)
+	}
+	return json.Marshal(m.String())
+}
+
+// parseModelType returns the ModelType for its API value. An empty string yields ModelTypeUnset.
+func parseModelType(s string) (ModelType, error) {
+	for i, name := range modelTypes {
+		if name == s {
+			return ModelType(i), nil
+		}
+	}
+	return ModelTypeUnset, fmt.Errorf("invalid model type %q", s)
+}
+
 // TranslateTextOptions holds the parameters for a text translation request.
+// The boolean options are pointers so that an explicit value always takes precedence; a nil value falls
+// back to the client default set by WithShowBilledCharacters, WithPreserveFormatting or WithOutlineDetection,
+// and without one, the parameter is omitted so that DeepL applies its own default.
 type TranslateTextOptions struct {
-	Text                 []string `json:"text"`                             // Text(s) to translate
-	SourceLang           string   `json:"source_lang,omitempty"`            // Source language code
-	TargetLang           string   `json:"target_lang"`                      // Target language code
-	Context              string   `json:"context,omitempty"`                // Additional context for translation
-	ShowBilledCharacters *bool    `json:"show_billed_characters,omitempty"` // Include billed character count in response
-	SplitSentences       string   `json:"split_sentences,omitempty"`        // Sentence splitting mode: "0", "1", or "nonewlines"
-	PreserveFormatting   *bool    `json:"preserve_formatting,omitempty"`    // Preserve original formatting
-	Formality            string   `json:"formality,omitempty"`              // Formality preference
-	ModelType            string   `json:"model_type,omitempty"`             // Translation model type
-	GlossaryID           string   `json:"glossary_id,omitempty"`            // Glossary ID to apply
-	TagHandling          string   `json:"tag_handling,omitempty"`           // Tag handling mode: "xml" or "html"
-	OutlineDetection     *bool    `json:"outline_detection,omitempty"`      // Enable XML outline detection (default true)
-	NonSplittingTags     []string `json:"non_splitting_tags,omitempty"`     // XML tags never splitting sentences
-	SplittingTags        []string `json:"splitting_tags,omitempty"`         // XML tags that split sentences
-	IgnoreTags           []string `json:"ignore_tags,omitempty"`            // XML tags marking untranslatable text
+	Text                 []string          `json:"text"`                             // Text(s) to translate
+	SourceLang           string            `json:"source_lang,omitempty"`            // Source language code, case-insensitive
+	TargetLang           string            `json:"target_lang"`                      // Target language code, case-insensitive
+	Context              string            `json:"context,omitempty"`                // Additional context for translation
+	ShowBilledCharacters *bool             `json:"show_billed_characters,omitempty"` // Include billed character count in response
+	SplitSentenceMode    SplitSentenceMode `json:"-"`                                // Sentence splitting mode, sent as split_sentences
+	PreserveFormatting   *bool             `json:"preserve_formatting,omitempty"`    // Preserve original formatting
+	FormalityLevel       Formality         `json:"-"`                                // Formality preference, sent as formality
+	Model                ModelType         `json:"-"`                                // Translation model type, sent as model_type
+	GlossaryID           string            `json:"glossary_id,omitempty"`            // Glossary ID to apply
+	TagHandling          string            `json:"tag_handling,omitempty"`           // Tag handling mode: "xml" or "html"
+	OutlineDetection     *bool             `json:"outline_detection,omitempty"`      // Enable XML outline detection (default true)
+	NonSplittingTags     []string          `json:"non_splitting_tags,omitempty"`     // XML tags never splitting sentences
+	SplittingTags        []string          `json:"splitting_tags,omitempty"`         // XML tags that split sentences
+	IgnoreTags           []string          `json:"ignore_tags,omitempty"`            // XML tags marking untranslatable text
+
+	// PreserveSurroundingWhitespace re-applies the leading and trailing whitespace of each text to its
+	// translation, as DeepL may trim it. It is handled by the client and not sent to the API.
+	PreserveSurroundingWhitespace bool `json:"-"`
+
+	// SplitSentences is the sentence splitting mode as its API value: "0", "1", or "nonewlines".
+	// It is only sent if SplitSentenceMode is unset.
+	//
+	// Deprecated: Use SplitSentenceMode, which cannot hold misspelled values.
+	SplitSentences string `json:"split_sentences,omitempty"`
+
+	// Formality is the formality preference as its API value, e.g. "more".
+	// It is only sent if FormalityLevel is unset.
+	//
+	// Deprecated: Use FormalityLevel, which distinguishes FormalityDefault from unset and cannot hold
+	// misspelled values.
+	Formality string `json:"formality,omitempty"`
+
+	// ModelType is the translation model type as its API value, e.g. "quality_optimized".
+	// It is only sent if Model is unset.
+	//
+	// Deprecated: Use Model, which cannot hold misspelled values.
+	ModelType string `json:"model_type,omitempty"`
+}
+
+// withEnumValues returns o with the string fields sent to the API set from the typed fields that take
+// precedence over them. It returns an error if a typed field holds an invalid value.
+func (o TranslateTextOptions) withEnumValues() (TranslateTextOptions, error) {
+	if o.SplitSentenceMode != SplitSentenceModeUnset {
+		if o.SplitSentenceMode.String() == "" {
+			return o, fmt.Errorf("invalid split sentences mode %d", o.SplitSentenceMode)
+		}
+		o.SplitSentences = o.SplitSentenceMode.String()
+	}
+	if o.FormalityLevel != FormalityUnset {
+		if o.FormalityLevel.String() == "" {
+			return o, fmt.Errorf("invalid formality value %d", o.FormalityLevel)
+		}
+		o.Formality = o.FormalityLevel.String()
+	}
+	if o.Model != ModelTypeUnset {
+		if o.Model.String() == "" {
+			return o, fmt.Errorf("invalid model type value %d", o.Model)
+		}
+		o.ModelType = o.Model.String()
+	}
+	return o, nil
+}
+
+// splitSentences returns the effective sentence splitting mode: SplitSentenceMode if set, and otherwise the
+// mode given by SplitSentences, or SplitSentenceModeUnset if it is not a valid API value.
+func (o TranslateTextOptions) splitSentences() SplitSentenceMode {
+	if o.SplitSentenceMode != SplitSentenceModeUnset {
+		return o.SplitSentenceMode
+	}
+	mode, _ := parseSplitSentenceMode(o.SplitSentences)
+	return mode
 }
 
 // Translation contains a single translation result corresponding to one input text.
@@ -41,6 +249,19 @@ type TranslationsResponse struct {
 	Translations []*Translation `json:"translations"` // Translations in same order as requested texts
 }
 
+// TotalBilledCharacters returns the sum of the characters billed for all translations. DeepL only reports billed
+// characters when TranslateTextOptions.ShowBilledCharacters is enabled; otherwise it returns 0.
+// Nil translations, as in the partial results of TranslateTexts, are skipped.
+func (r *TranslationsResponse) TotalBilledCharacters() int {
+	total := 0
+	for _, translation := range r.Translations {
+		if translation != nil {
+			total += translation.BilledCharacters
+		}
+	}
+	return total
+}
+
 // TranslateText translates a single text string into the target language using default options.
 // It uses a background context.
 func (c *Client) TranslateText(text, targetLanguage string) (*Translation, error) {
@@ -57,15 +278,28 @@ func (c *Client) TranslateTextWithContext(ctx context.Context, text, targetLangu
 	if err != nil {
 		return nil, err
 	}
-	if len(translations) == 0 {
-		return nil, errors.New("no translation returned")
-	}
 	return translations[0], nil
 }
 
 // TranslateTextWithOptions translates one or more texts with full control via TranslateTextOptions.
 // Supports context for cancellation and timeout.
 func (c *Client) TranslateTextWithOptions(ctx context.Context, opts TranslateTextOptions) ([]*Translation, error) {
+	return c.translateTextWithOptions(ctx, opts, false)
+}
+
+// translateTextWithOptions implements TranslateTextWithOptions. If detect is set, the texts are translated only
+// to detect their source language, so WithRequiredSourceLang does not apply.
+func (c *Client) translateTextWithOptions(ctx context.Context, opts TranslateTextOptions, detect bool) ([]*Translation, error) {
+	opts, err := c.checkTranslateTextOptions(c.applyTranslateDefaults(opts), detect)
+	if err != nil {
+		return nil, err
+	}
+	opts, err = opts.withEnumValues()
+	if err != nil {
+		return nil, err
+	}
+	opts.SourceLang = normalizeLangCode(opts.SourceLang)
+	opts.TargetLang = normalizeLangCode(opts.TargetLang)
 	data, err := json.Marshal(opts)
 	if err != nil {
 		return nil, err
@@ -75,9 +309,164 @@ func (c *Client) TranslateTextWithOptions(ctx context.Context, opts TranslateTex
 	if err != nil {
 		return nil, err
 	}
-	var response TranslationsResponse
-	if err := c.doRequest(ctx, req, &response); err != nil {
+	response, err := doJSON[TranslationsResponse](c, ctx, req)
+	if err != nil {
+		if isTargetLangUnsupported(err) {
+			if suggestion := suggestLanguage(opts.TargetLang, c.cachedLanguages(LanguageTypeTarget)); suggestion != "" {
+				return nil, fmt.Errorf("%w (did you mean %s?)", err, suggestion)
+			}
+		}
+		return nil, err
+	}
+	if err := checkNotEmpty(response.Translations, "translation"); err != nil {
 		return nil, err
 	}
+	for i, translation := range response.Translations {
+		if translation == nil {
+			continue
+		}
+		if translation.BilledCharacters > 0 {
+			c.billedCharacters.Add(int64(translation.BilledCharacters))
+		}
+		if opts.PreserveSurroundingWhitespace && i < len(opts.Text) {
+			translation.Text = withSurroundingWhitespace(opts.Text[i], translation.Text)
+		}
+		if c.normalization != nil {
+			translation.Text = c.normalization.String(translation.Text)
+		}
+	}
 	return response.Translations, nil
 }
+
+// WithUnicodeNormalization returns an Option that normalizes every translated text to the given Unicode
+// normalization form before it is returned, e.g. norm.NFC for downstream systems that require composed
+// characters. It applies to all text translation methods, which pass through TranslateTextWithOptions.
+func WithUnicodeNormalization(form norm.Form) Option {
+	return func(c *Client) {
+		c.normalization = &form
+	}
+}
+
+// normalizeLangCode returns a language code in the upper case DeepL expects, e.g. "EN-GB" for "en-gb".
+func normalizeLangCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// withSurroundingWhitespace returns translated with its own surrounding whitespace replaced by that of source.
+func withSurroundingWhitespace(source, translated string) string {
+	core := strings.TrimLeftFunc(source, unicode.IsSpace)
+	leading := source[:len(source)-len(core)]
+	trailing := core[len(strings.TrimRightFunc(core, unicode.IsSpace)):]
+	return leading + strings.TrimSpace(translated) + trailing
+}
+
+// TotalBilledCharacters returns the number of characters billed for all translations made by this client so far.
+// DeepL only reports billed characters when TranslateTextOptions.ShowBilledCharacters is enabled, so translations
+// without the count do not contribute. It is safe for concurrent use and does not call the API.
+func (c *Client) TotalBilledCharacters() int64 {
+	return c.billedCharacters.Load()
+}
+
+// TranslateTextPreferVariant translates a single text string into preferredVariant (e.g. "EN-US") and falls back to
+// baseLang (e.g. "EN") if DeepL rejects the variant as a target language. It returns the translation together with
+// the target language that was actually used. Any other failure is returned as is, without a fallback.
+func (c *Client) TranslateTextPreferVariant(ctx context.Context, text, preferredVariant, baseLang string) (*Translation, string, error) {
+	translation, err := c.TranslateTextWithContext(ctx, text, preferredVariant)
+	if err == nil {
+		return translation, preferredVariant, nil
+	}
+	if !isTargetLangUnsupported(err) || strings.EqualFold(preferredVariant, baseLang) {
+		return nil, "", err
+	}
+	translation, err = c.TranslateTextWithContext(ctx, text, baseLang)
+	if err != nil {
+		return nil, "", err
+	}
+	return translation, baseLang, nil
+}
+
+// isTargetLangUnsupported reports whether err is DeepL's response to an unsupported target language, which it
+// signals with a 400 Bad Request whose message refers to the target_lang parameter.
+func isTargetLangUnsupported(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	return strings.Contains(strings.ToLower(apiErr.Message), "target_lang")
+}
+
+// DetailedTranslation bundles a translated text with the metadata DeepL reports about it.
+type DetailedTranslation struct {
+	text                   string
+	detectedSourceLanguage string
+	billedCharacters       int
+	modelTypeUsed          string
+}
+
+// Text returns the translated text.
+func (d *DetailedTranslation) Text() string {
+	return d.text
+}
+
+// DetectedSourceLanguage returns the source language code detected by DeepL, e.g. "EN".
+func (d *DetailedTranslation) DetectedSourceLanguage() string {
+	return d.detectedSourceLanguage
+}
+
+// BilledCharacters returns the number of characters billed for the translation.
+func (d *DetailedTranslation) BilledCharacters() int {
+	return d.billedCharacters
+}
+
+// ModelTypeUsed returns the model DeepL used for the translation, or an empty string if it was not reported.
+func (d *DetailedTranslation) ModelTypeUsed() string {
+	return d.modelTypeUsed
+}
+
+// TranslateDetailed translates a single text string into the target language and returns the translation
+// together with the detected source language, billed characters, and model used. Billed characters are
+// requested automatically.
+func (c *Client) TranslateDetailed(ctx context.Context, text, targetLang string) (*DetailedTranslation, error) {
+	translations, err := c.TranslateTextWithOptions(ctx, TranslateTextOptions{
+		Text:                 []string{text},
+		TargetLang:           targetLang,
+		ShowBilledCharacters: True(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	translation := translations[0]
+	return &DetailedTranslation{
+		text:                   translation.Text,
+		detectedSourceLanguage: translation.DetectedSourceLanguage,
+		billedCharacters:       translation.BilledCharacters,
+		modelTypeUsed:          translation.ModelTypeUsed,
+	}, nil
+}
+
+// DetectLanguage returns the source language DeepL detects for text, e.g. "EN". DeepL has no dedicated
+// detection endpoint, so the text is translated and only the detected language is returned; this still
+// counts the characters of text against the account's quota. As detection is its purpose, DetectLanguage
+// also works on a client created with WithRequiredSourceLang.
+func (c *Client) DetectLanguage(ctx context.Context, text string) (string, error) {
+	translations, err := c.translateTextWithOptions(ctx, TranslateTextOptions{
+		Text:       []string{text},
+		TargetLang: "EN-US",
+	}, true)
+	if err != nil {
+		return "", err
+	}
+	if translations[0].DetectedSourceLanguage == "" {
+		return "", errors.New("no source language detected")
+	}
+	return translations[0].DetectedSourceLanguage, nil
+}
+
+// TranslateTextWithin translates a single text string like TranslateTextWithContext but gives up once budget
+// has elapsed, e.g. for latency-sensitive user interfaces. Retries are limited to those whose backoff still
+// fits into the budget. If the budget is exceeded, the returned error wraps context.DeadlineExceeded.
+func (c *Client) TranslateTextWithin(ctx context.Context, text, targetLanguage string, budget time.Duration) (*Translation, error) {
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+	return c.TranslateTextWithContext(ctx, text, targetLanguage)
+}
@@ -3,10 +3,15 @@ package deepl
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 func TestTranslateText(t *testing.T) {
@@ -187,3 +192,477 @@ func TestTranslateTextErrors(t *testing.T) {
 		}
 	})
 }
+
+func TestTranslateTextWithOptions_Formality(t *testing.T) {
+	testCases := []struct {
+		name      string
+		formality Formality
+		legacy    string
+		expected  string
+	}{
+		{"unset is omitted", FormalityUnset, "", ""},
+		{"default is sent explicitly", FormalityDefault, "", `"formality":"default"`},
+		{"prefer less", FormalityPreferLess, "", `"formality":"prefer_less"`},
+		{"deprecated string field", FormalityUnset, "less", `"formality":"less"`},
+		{"typed field takes precedence", FormalityMore, "less", `"formality":"more"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				body, _ := io.ReadAll(req.Body)
+				if tc.expected == "" && strings.Contains(string(body), "formality") {
+					t.Errorf("expected formality to be omitted, got body %s", body)
+				}
+				if tc.expected != "" && !strings.Contains(string(body), tc.expected) {
+					t.Errorf("expected body to contain %s, got %s", tc.expected, body)
+				}
+				return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+			})
+
+			_, err := client.TranslateTextWithOptions(context.Background(), TranslateTextOptions{
+				Text:           []string{"Hello"},
+				TargetLang:     "DE",
+				FormalityLevel: tc.formality,
+				Formality:      tc.legacy,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTranslateTextWithOptions_UppercasesLanguageCodes(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		for _, expected := range []string{`"source_lang":"DE"`, `"target_lang":"EN-US"`} {
+			if !strings.Contains(string(body), expected) {
+				t.Errorf("expected body to contain %s, got %s", expected, body)
+			}
+		}
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hello"}}})
+	})
+
+	_, err := client.TranslateTextWithOptions(context.Background(), TranslateTextOptions{
+		Text:       []string{"Hallo"},
+		SourceLang: "de",
+		TargetLang: "en-us",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFormality_JSON(t *testing.T) {
+	for f := FormalityDefault; f <= FormalityPreferLess; f++ {
+		data, err := json.Marshal(f)
+		if err != nil {
+			t.Fatalf("unexpected error marshaling %d: %v", f, err)
+		}
+		var decoded Formality
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unexpected error unmarshaling %s: %v", data, err)
+		}
+		if decoded != f {
+			t.Errorf("expected %d after round trip of %s, got %d", f, data, decoded)
+		}
+	}
+
+	if _, err := json.Marshal(Formality(42)); err == nil {
+		t.Error("expected error marshaling an out-of-range formality")
+	}
+	if Formality(42).String() != "" {
+		t.Errorf("expected empty string for an out-of-range formality, got %q", Formality(42).String())
+	}
+
+	var decoded Formality
+	if err := json.Unmarshal([]byte(`"polite"`), &decoded); err == nil {
+		t.Error("expected error unmarshaling an unknown formality")
+	}
+}
+
+func TestSplitSentenceModeAndModelType_JSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     TranslateTextOptions
+		expected []string
+		omitted  []string
+	}{
+		{"typed fields", TranslateTextOptions{SplitSentenceMode: SplitSentenceModeNoNewlines, Model: ModelTypeQualityOptimized},
+			[]string{`"split_sentences":"nonewlines"`, `"model_type":"quality_optimized"`}, nil},
+		{"deprecated string fields", TranslateTextOptions{SplitSentences: "0", ModelType: "latency_optimized"},
+			[]string{`"split_sentences":"0"`, `"model_type":"latency_optimized"`}, nil},
+		{"typed fields take precedence", TranslateTextOptions{SplitSentenceMode: SplitSentenceModeOn, SplitSentences: "0", Model: ModelTypePreferQualityOptimized, ModelType: "latency_optimized"},
+			[]string{`"split_sentences":"1"`, `"model_type":"prefer_quality_optimized"`}, nil},
+		{"unset", TranslateTextOptions{}, nil, []string{"split_sentences", "model_type"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				body, _ := io.ReadAll(req.Body)
+				for _, expected := range tc.expected {
+					if !strings.Contains(string(body), expected) {
+						t.Errorf("expected body to contain %s, got %s", expected, body)
+					}
+				}
+				for _, omitted := range tc.omitted {
+					if strings.Contains(string(body), omitted) {
+						t.Errorf("expected %s to be omitted, got %s", omitted, body)
+					}
+				}
+				return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+			})
+
+			opts := tc.opts
+			opts.Text = []string{"Hello"}
+			opts.TargetLang = "DE"
+			if _, err := client.TranslateTextWithOptions(context.Background(), opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	client := NewTestClient(nil)
+	_, err := client.TranslateTextWithOptions(context.Background(), TranslateTextOptions{Text: []string{"Hello"}, TargetLang: "DE", Model: ModelType(42)})
+	if err == nil || !strings.Contains(err.Error(), "invalid model type") {
+		t.Errorf("expected invalid model type error, got %v", err)
+	}
+
+	values := []struct {
+		value    any
+		expected string
+	}{
+		{SplitSentenceModeOff, `"0"`},
+		{SplitSentenceModeOn, `"1"`},
+		{SplitSentenceModeNoNewlines, `"nonewlines"`},
+		{ModelTypeLatencyOptimized, `"latency_optimized"`},
+		{ModelTypeQualityOptimized, `"quality_optimized"`},
+		{ModelTypePreferQualityOptimized, `"prefer_quality_optimized"`},
+	}
+	for _, tc := range values {
+		data, err := json.Marshal(tc.value)
+		if err != nil {
+			t.Fatalf("unexpected error marshaling %v: %v", tc.value, err)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, data)
+		}
+	}
+
+	if _, err := json.Marshal(SplitSentenceMode(42)); err == nil {
+		t.Error("expected error marshaling an out-of-range split sentences mode")
+	}
+	if _, err := json.Marshal(ModelType(42)); err == nil {
+		t.Error("expected error marshaling an out-of-range model type")
+	}
+}
+
+func TestTranslateTextPreferVariant_FallsBackToBase(t *testing.T) {
+	var targets []string
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		var opts TranslateTextOptions
+		body, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(body, &opts)
+		targets = append(targets, opts.TargetLang)
+
+		if opts.TargetLang == "PT-XX" {
+			return MockResponse(400, map[string]string{"message": "Value for 'target_lang' not supported."})
+		}
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{{DetectedSourceLanguage: "EN", Text: "Olá mundo"}},
+		})
+	})
+
+	translation, used, err := client.TranslateTextPreferVariant(context.Background(), "Hello world", "PT-XX", "PT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if used != "PT" {
+		t.Errorf("expected fallback target PT, got %q", used)
+	}
+	if translation.Text != "Olá mundo" {
+		t.Errorf("unexpected translation: %q", translation.Text)
+	}
+	if strings.Join(targets, ",") != "PT-XX,PT" {
+		t.Errorf("expected requests for PT-XX then PT, got %v", targets)
+	}
+}
+
+func TestTranslateTextPreferVariant_NoFallbackOnOtherErrors(t *testing.T) {
+	calls := 0
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return MockResponse(403, map[string]string{"message": "Wrong API key"})
+	})
+
+	_, used, err := client.TranslateTextPreferVariant(context.Background(), "Hello world", "EN-US", "EN")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if used != "" {
+		t.Errorf("expected no target on failure, got %q", used)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single request without fallback, got %d", calls)
+	}
+}
+
+func TestTotalBilledCharacters(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{
+				{Text: "Hallo", BilledCharacters: 5},
+				{Text: "Welt", BilledCharacters: 4},
+			},
+		})
+	})
+
+	opts := TranslateTextOptions{
+		Text:                 []string{"Hello", "World"},
+		TargetLang:           "DE",
+		ShowBilledCharacters: True(),
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := client.TranslateTextWithOptions(context.Background(), opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := client.TotalBilledCharacters(); got != 27 {
+		t.Errorf("expected 27 billed characters, got %d", got)
+	}
+}
+
+func TestTotalBilledCharacters_Concurrent(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{{Text: "Hallo", BilledCharacters: 5}},
+		})
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.TranslateTextWithContext(context.Background(), "Hello", "DE"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := client.TotalBilledCharacters(); got != 100 {
+		t.Errorf("expected 100 billed characters, got %d", got)
+	}
+}
+
+func TestTranslationsResponse_TotalBilledCharacters(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"sums all translations", `{"translations":[{"text":"Hallo","billed_characters":5},{"text":"Welt","billed_characters":4},{"text":"!","billed_characters":1}]}`, 10},
+		{"field absent", `{"translations":[{"text":"Hallo"},{"text":"Welt"}]}`, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var response TranslationsResponse
+			if err := json.Unmarshal([]byte(tc.body), &response); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := response.TotalBilledCharacters(); got != tc.expected {
+				t.Errorf("expected %d billed characters, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTranslateDetailed(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		if !strings.Contains(string(body), `"show_billed_characters":true`) {
+			t.Errorf("expected show_billed_characters to be requested, got %s", body)
+		}
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{{
+				DetectedSourceLanguage: "EN",
+				Text:                   "Hallo Welt",
+				BilledCharacters:       11,
+				ModelTypeUsed:          "quality_optimized",
+			}},
+		})
+	})
+
+	detailed, err := client.TranslateDetailed(context.Background(), "Hello world", "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if detailed.Text() != "Hallo Welt" {
+		t.Errorf("expected text 'Hallo Welt', got %q", detailed.Text())
+	}
+	if detailed.DetectedSourceLanguage() != "EN" {
+		t.Errorf("expected detected source language 'EN', got %q", detailed.DetectedSourceLanguage())
+	}
+	if detailed.BilledCharacters() != 11 {
+		t.Errorf("expected 11 billed characters, got %d", detailed.BilledCharacters())
+	}
+	if detailed.ModelTypeUsed() != "quality_optimized" {
+		t.Errorf("expected model 'quality_optimized', got %q", detailed.ModelTypeUsed())
+	}
+}
+
+func TestTranslateTextWithOptions_PreserveSurroundingWhitespace(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		if strings.Contains(string(body), "preserve_surrounding_whitespace") || strings.Contains(string(body), "PreserveSurroundingWhitespace") {
+			t.Errorf("expected client-side option not to be sent, got %s", body)
+		}
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{
+				{Text: "Hallo"},
+				{Text: "Welt "},
+				{Text: "Tschüss"},
+			},
+		})
+	})
+
+	translations, err := client.TranslateTextWithOptions(context.Background(), TranslateTextOptions{
+		Text:                          []string{" Hello", "World\n", "Bye"},
+		TargetLang:                    "DE",
+		PreserveSurroundingWhitespace: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{" Hallo", "Welt\n", "Tschüss"}
+	for i, translation := range translations {
+		if translation.Text != expected[i] {
+			t.Errorf("text %d: expected %q, got %q", i, expected[i], translation.Text)
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		var opts TranslateTextOptions
+		_ = json.Unmarshal(body, &opts)
+		if opts.SourceLang != "" {
+			t.Errorf("expected source language to be detected, got %q", opts.SourceLang)
+		}
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{{DetectedSourceLanguage: "DE", Text: "Good morning"}},
+		})
+	})
+
+	lang, err := client.DetectLanguage(context.Background(), "Guten Morgen")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lang != "DE" {
+		t.Errorf("expected detected language 'DE', got %q", lang)
+	}
+}
+
+func TestDetectLanguage_RequiredSourceLang(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, TranslationsResponse{
+			Translations: []*Translation{{DetectedSourceLanguage: "DE", Text: "Good morning"}},
+		})
+	})
+	WithRequiredSourceLang()(client)
+
+	lang, err := client.DetectLanguage(context.Background(), "Guten Morgen")
+	if err != nil {
+		t.Fatalf("expected DetectLanguage to be exempt from WithRequiredSourceLang, got %v", err)
+	}
+	if lang != "DE" {
+		t.Errorf("expected detected language 'DE', got %q", lang)
+	}
+	if _, err := client.TranslateText("Guten Morgen", "EN-US"); !errors.Is(err, ErrSourceLangRequired) {
+		t.Errorf("expected translations to still require the source language, got %v", err)
+	}
+}
+
+func TestDetectLanguage_NoTranslations(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{}})
+	})
+
+	_, err := client.DetectLanguage(context.Background(), "Guten Morgen")
+	if !errors.Is(err, ErrEmptyResult) || !strings.Contains(err.Error(), "no translation returned") {
+		t.Errorf("expected ErrEmptyResult for no translation, got %v", err)
+	}
+}
+
+func TestTranslateTextWithin_Timeout(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		select {
+		case <-req.Context().Done():
+			return nil
+		case <-time.After(2 * time.Second):
+			return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+		}
+	})
+	client.retryPolicy = defaultRetryPolicy
+
+	start := time.Now()
+	_, err := client.TranslateTextWithin(context.Background(), "Hello", "DE", 100*time.Millisecond)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("expected a timely timeout, took %v", elapsed)
+	}
+}
+
+func TestTranslateTextWithin_Success(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if _, ok := req.Context().Deadline(); !ok {
+			t.Error("expected the request to carry the budget as deadline")
+		}
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+	})
+
+	translation, err := client.TranslateTextWithin(context.Background(), "Hello", "DE", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.Text != "Hallo" {
+		t.Errorf("expected 'Hallo', got %q", translation.Text)
+	}
+}
+
+func TestWithUnicodeNormalization(t *testing.T) {
+	decomposed := "Cafe\u0301 cre\u0300me"
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: decomposed}}})
+	})
+
+	translation, err := client.TranslateText("Coffee cream", "FR")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.Text != decomposed {
+		t.Errorf("expected the text to be left as is without normalization, got %q", translation.Text)
+	}
+
+	WithUnicodeNormalization(norm.NFC)(client)
+	translation, err = client.TranslateText("Coffee cream", "FR")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.Text != "Caf\u00e9 cr\u00e8me" {
+		t.Errorf("expected NFC text %q, got %q", "Caf\u00e9 cr\u00e8me", translation.Text)
+	}
+}
@@ -2,8 +2,13 @@ package deepl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,6 +21,55 @@ type Usage struct {
 	APIKeyCharacterLimit *int64         `json:"api_key_character_limit,omitempty"` // Character limit specific to the API key (optional)
 	StartTime            *time.Time     `json:"start_time,omitempty"`              // Start time of current usage period (optional)
 	EndTime              *time.Time     `json:"end_time,omitempty"`                // End time of current usage period (optional)
+
+	periods []PeriodUsage // Usage per time window, only returned by some plans
+}
+
+// PeriodUsage describes the character usage within a single time window of the billing period.
+type PeriodUsage struct {
+	StartTime      time.Time `json:"start_time"`      // Start of the time window
+	EndTime        time.Time `json:"end_time"`        // End of the time window
+	CharacterCount int64     `json:"character_count"` // Characters translated within the time window
+	CharacterLimit int64     `json:"character_limit"` // Character limit of the time window, 0 if unlimited
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Usage.
+// The optional "periods" array is decoded tolerantly: if it is missing or malformed, Periods returns nil
+// and the remaining fields are decoded as usual.
+func (u *Usage) UnmarshalJSON(data []byte) error {
+	type usageAlias Usage
+	aux := struct {
+		*usageAlias
+		Periods json.RawMessage `json:"periods"`
+	}{usageAlias: (*usageAlias)(u)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	u.periods = nil
+	var periods []PeriodUsage
+	if len(aux.Periods) > 0 && json.Unmarshal(aux.Periods, &periods) == nil {
+		u.periods = periods
+	}
+	return nil
+}
+
+// Periods returns the usage broken down by time window, or nil if the plan does not report it.
+func (u *Usage) Periods() []PeriodUsage {
+	return u.periods
+}
+
+// NextResetTime returns when the character count of the current billing period is reset, i.e. the end of
+// the period. If DeepL only reports the start of the period, the reset is assumed one month after it.
+// It reports false if the response contains neither, as for plans without period information.
+func (u *Usage) NextResetTime() (time.Time, bool) {
+	switch {
+	case u.EndTime != nil && !u.EndTime.IsZero():
+		return *u.EndTime, true
+	case u.StartTime != nil && !u.StartTime.IsZero():
+		return u.StartTime.AddDate(0, 1, 0), true
+	}
+	return time.Time{}, false
 }
 
 // ProductUsage provides detailed usage information related to a specific DeepL product.
@@ -25,7 +79,68 @@ type ProductUsage struct {
 	CharacterCount       int64  `json:"character_count"`         // Total characters translated using this product
 }
 
+// String returns a human-readable one-line summary of the character usage, e.g.
+// "2,150,000 / 20,000,000 characters (10.8%)", or "2,150,000 characters (unlimited)" if the account has no limit.
+func (u *Usage) String() string {
+	if u.CharacterLimit <= 0 {
+		return fmt.Sprintf("%s characters (unlimited)", formatThousands(u.CharacterCount))
+	}
+	percent := float64(u.CharacterCount) / float64(u.CharacterLimit) * 100
+	return fmt.Sprintf("%s / %s characters (%.1f%%)", formatThousands(u.CharacterCount), formatThousands(u.CharacterLimit), percent)
+}
+
+// IsNearLimit reports whether the character count has reached the given fraction of the character limit,
+// e.g. 0.9 for 90%. Accounts without a character limit are never near their limit.
+func (u *Usage) IsNearLimit(threshold float64) bool {
+	return u.CharacterLimit > 0 && u.UsageRatio() >= threshold
+}
+
+// UsageRatio returns the character count as a fraction of the character limit, e.g. 0.25 for 25%.
+// It returns 0 for accounts without a character limit.
+func (u *Usage) UsageRatio() float64 {
+	if u.CharacterLimit <= 0 {
+		return 0
+	}
+	return float64(u.CharacterCount) / float64(u.CharacterLimit)
+}
+
+// LimitReached reports whether the character count has reached the character limit, so that further
+// translations fail with a quota error. Accounts without a character limit never reach it.
+func (u *Usage) LimitReached() bool {
+	return u.CharacterLimit > 0 && u.CharacterCount >= u.CharacterLimit
+}
+
+// ProductUsageByType returns the usage of the product with the given type, e.g. "write", or nil if the
+// response contains no usage for it.
+func (u *Usage) ProductUsageByType(productType string) *ProductUsage {
+	for i := range u.Products {
+		if u.Products[i].ProductType == productType {
+			return &u.Products[i]
+		}
+	}
+	return nil
+}
+
+// formatThousands formats n with commas as thousands separators, independent of the locale.
+func formatThousands(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var out []byte
+	for i := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, digits[i])
+	}
+	return sign + string(out)
+}
+
 // GetUsage retrieves the current account API usage.
+// As a read-only request, it is retried on transient failures according to the client's retry policy.
 func (c *Client) GetUsage() (*Usage, error) {
 	return c.GetUsageWithContext(context.Background())
 }
@@ -37,11 +152,111 @@ func (c *Client) GetUsageWithContext(ctx context.Context) (*Usage, error) {
 		return nil, err
 	}
 
-	var res Usage
+	return doJSON[Usage](c, ctx, req)
+}
+
+// AccountInfo summarizes the account behind an API key.
+type AccountInfo struct {
+	IsFree         bool  // Whether the API key belongs to a DeepL API Free account
+	CharacterLimit int64 // Character limit of the current billing period, 0 if unlimited
+	CharacterCount int64 // Characters translated in the current billing period
+}
+
+// Authenticate verifies the API key by retrieving the account usage and returns the account's plan details.
+// If DeepL rejects the key with 403 Forbidden, the returned error wraps ErrAuthFailed.
+func (c *Client) Authenticate(ctx context.Context) (AccountInfo, error) {
+	usage, err := c.GetUsageWithContext(ctx)
+	if err != nil {
+		return AccountInfo{}, wrapAuthError(err)
+	}
+
+	return AccountInfo{
+		IsFree:         strings.HasSuffix(c.apiKey, ":fx"),
+		CharacterLimit: usage.CharacterLimit,
+		CharacterCount: usage.CharacterCount,
+	}, nil
+}
 
-	if err := c.doRequest(ctx, req, &res); err != nil {
-		return nil, err
+// Ping verifies the API key with a single request for the account usage, whose body is not decoded, e.g. as a
+// fail-fast credential check on startup. It returns nil if DeepL accepts the key. If DeepL rejects the key with
+// 403 Forbidden, the returned error wraps ErrAuthFailed and IsAuthError reports true; other failures, such as
+// network errors, are wrapped as they are.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/v2/usage", c.baseURL), nil)
+	if err != nil {
+		return err
+	}
+
+	err = c.doRequestRaw(ctx, req, func(io.Reader) error { return nil })
+	if hasStatusCode(err, http.StatusForbidden) {
+		return wrapAuthError(err)
+	}
+	if err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	return nil
+}
+
+// wrapAuthError wraps err with ErrAuthFailed if it is an *APIError for 403 Forbidden and returns it unchanged otherwise.
+func wrapAuthError(err error) error {
+	if hasStatusCode(err, http.StatusForbidden) {
+		return fmt.Errorf("%w: %w", ErrAuthFailed, err)
+	}
+	return err
+}
+
+// Warmup establishes a connection to the DeepL API ahead of the first real request by retrieving the account
+// usage, which is neither billed nor counted against the character limit. This moves the latency of the
+// TLS handshake out of the first translation, e.g. in short-lived or serverless processes.
+// Warmup may be called any number of times; each call issues a single request.
+func (c *Client) Warmup(ctx context.Context) error {
+	_, err := c.GetUsageWithContext(ctx)
+	return err
+}
+
+// Request headers understood by the DeepL mock server: requests with the same session ID share a session, and
+// the 429 count makes the mock answer that many requests of a new session with 429 Too Many Requests.
+const (
+	mockSessionHeader    = "mock-server-session"
+	mockSession429Header = "mock-server-session-429-count"
+)
+
+// IsMockServer reports whether the client talks to the DeepL mock server (https://github.com/DeepLcom/deepl-mock)
+// rather than the DeepL API, e.g. for test suites to make sure they cannot cause real, billed translations.
+//
+// It retrieves the account usage once, without retries, in a new mock session that asks for the first request
+// to be answered with 429. The server counts as the mock only if it does so and the response carries the
+// X-Powered-By header of the Express framework the mock is built on. The DeepL API ignores the session headers,
+// so neither an Express-based proxy in front of it nor a 429 alone is taken for the mock. The check relies on
+// these mock features, though: a mock without them is reported as the DeepL API, and an Express-based proxy
+// whose DeepL API key happens to be rate-limited at that moment is reported as the mock.
+func (c *Client) IsMockServer(ctx context.Context) (bool, error) {
+	if c.configErr != nil {
+		return false, c.configErr
 	}
+	ctx, cancel := c.withBaseContext(ctx)
+	defer cancel()
 
-	return &res, nil
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/v2/usage", c.baseURL), nil)
+	if err != nil {
+		return false, err
+	}
+	c.setRequestHeaders(req)
+	req.Header.Set(mockSessionHeader, fmt.Sprintf("deepl-go-probe-%d", rand.Int63()))
+	req.Header.Set(mockSession429Header, "1")
+
+	// The probe is sent once, as a retry would already be answered normally by the mock.
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		_ = resp.Body.Close()
+		return strings.EqualFold(resp.Header.Get("X-Powered-By"), "Express"), nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return false, createErrorFromResponse(resp)
+	}
+	_ = resp.Body.Close()
+	return false, nil
 }
@@ -2,6 +2,9 @@ package deepl
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -103,3 +106,412 @@ func TestGetUsageError(t *testing.T) {
 		t.Error("Expected error from GetUsageWithContext, got: nil")
 	}
 }
+
+func TestUsageString(t *testing.T) {
+	testCases := []struct {
+		name     string
+		usage    Usage
+		expected string
+	}{
+		{"limited", Usage{CharacterCount: 2150000, CharacterLimit: 20000000}, "2,150,000 / 20,000,000 characters (10.8%)"},
+		{"small numbers", Usage{CharacterCount: 999, CharacterLimit: 1000}, "999 / 1,000 characters (99.9%)"},
+		{"unlimited", Usage{CharacterCount: 2150000, CharacterLimit: 0}, "2,150,000 characters (unlimited)"},
+		{"empty", Usage{}, "0 characters (unlimited)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.usage.String(); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatThousands(t *testing.T) {
+	testCases := map[int64]string{
+		0:          "0",
+		12:         "12",
+		123:        "123",
+		1234:       "1,234",
+		123456:     "123,456",
+		1234567:    "1,234,567",
+		-1234567:   "-1,234,567",
+		1000000000: "1,000,000,000",
+	}
+
+	for n, expected := range testCases {
+		if got := formatThousands(n); got != expected {
+			t.Errorf("formatThousands(%d) = %q, expected %q", n, got, expected)
+		}
+	}
+}
+
+func TestGetUsage_Periods(t *testing.T) {
+	testCases := []struct {
+		name            string
+		body            string
+		expectedPeriods int
+	}{
+		{
+			name: "with periods",
+			body: `{"character_count":300,"character_limit":1000,"periods":[` +
+				`{"start_time":"2025-01-01T00:00:00Z","end_time":"2025-01-31T23:59:59Z","character_count":100,"character_limit":500},` +
+				`{"start_time":"2025-02-01T00:00:00Z","end_time":"2025-02-28T23:59:59Z","character_count":200,"character_limit":500}]}`,
+			expectedPeriods: 2,
+		},
+		{
+			name:            "without periods",
+			body:            `{"character_count":300,"character_limit":1000}`,
+			expectedPeriods: 0,
+		},
+		{
+			name:            "malformed periods",
+			body:            `{"character_count":300,"character_limit":1000,"periods":"monthly"}`,
+			expectedPeriods: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(tc.body)),
+					Header:     make(http.Header),
+				}
+			})
+
+			usage, err := client.GetUsage()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if usage.CharacterCount != 300 || usage.CharacterLimit != 1000 {
+				t.Errorf("expected flat fields to be decoded, got %+v", usage)
+			}
+
+			periods := usage.Periods()
+			if len(periods) != tc.expectedPeriods {
+				t.Fatalf("expected %d periods, got %d", tc.expectedPeriods, len(periods))
+			}
+			if tc.expectedPeriods > 0 {
+				if periods[1].CharacterCount != 200 || periods[1].CharacterLimit != 500 {
+					t.Errorf("unexpected second period: %+v", periods[1])
+				}
+				if !periods[0].StartTime.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+					t.Errorf("unexpected start time of first period: %v", periods[0].StartTime)
+				}
+			}
+		})
+	}
+}
+
+func TestUsageIsNearLimit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		usage    Usage
+		expected bool
+	}{
+		{"89 percent", Usage{CharacterCount: 445000, CharacterLimit: 500000}, false},
+		{"90 percent", Usage{CharacterCount: 450000, CharacterLimit: 500000}, true},
+		{"over limit", Usage{CharacterCount: 510000, CharacterLimit: 500000}, true},
+		{"unlimited", Usage{CharacterCount: 450000, CharacterLimit: 0}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.usage.IsNearLimit(0.9); got != tc.expected {
+				t.Errorf("expected IsNearLimit(0.9) = %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestUsageRatioAndLimitReached(t *testing.T) {
+	testCases := []struct {
+		name          string
+		usage         Usage
+		expectedRatio float64
+		expectedLimit bool
+	}{
+		{"quarter", Usage{CharacterCount: 125000, CharacterLimit: 500000}, 0.25, false},
+		{"at limit", Usage{CharacterCount: 500000, CharacterLimit: 500000}, 1, true},
+		{"over limit", Usage{CharacterCount: 510000, CharacterLimit: 500000}, 1.02, true},
+		{"unlimited", Usage{CharacterCount: 450000, CharacterLimit: 0}, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.usage.UsageRatio(); got != tc.expectedRatio {
+				t.Errorf("expected UsageRatio() = %v, got %v", tc.expectedRatio, got)
+			}
+			if got := tc.usage.LimitReached(); got != tc.expectedLimit {
+				t.Errorf("expected LimitReached() = %v, got %v", tc.expectedLimit, got)
+			}
+		})
+	}
+}
+
+func TestUsageProductUsageByType(t *testing.T) {
+	usage := Usage{Products: []ProductUsage{
+		{ProductType: "translate", CharacterCount: 1000},
+		{ProductType: "write", CharacterCount: 200},
+	}}
+
+	product := usage.ProductUsageByType("write")
+	if product == nil || product.CharacterCount != 200 {
+		t.Errorf("expected write usage with 200 characters, got %+v", product)
+	}
+	if product := usage.ProductUsageByType("speech"); product != nil {
+		t.Errorf("expected nil for a missing product type, got %+v", product)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(200, Usage{CharacterCount: 1200, CharacterLimit: 500000})
+	})
+	client.apiKey = "test-api-key:fx"
+
+	info, err := client.Authenticate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := AccountInfo{IsFree: true, CharacterLimit: 500000, CharacterCount: 1200}
+	if info != expected {
+		t.Errorf("expected %+v, got %+v", expected, info)
+	}
+}
+
+func TestAuthenticate_InvalidKey(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(403, map[string]string{"message": "Wrong endpoint"})
+	})
+
+	_, err := client.Authenticate(context.Background())
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("expected ErrAuthFailed, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 403 {
+		t.Errorf("expected wrapped *APIError with status 403, got %v", err)
+	}
+}
+
+func TestAuthenticate_OtherError(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(400, nil)
+	})
+
+	_, err := client.Authenticate(context.Background())
+	if err == nil || errors.Is(err, ErrAuthFailed) {
+		t.Errorf("expected non-auth error, got %v", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path != "/v2/usage" {
+			t.Errorf("expected path /v2/usage, got %s", req.URL.Path)
+		}
+		return MockResponse(200, Usage{CharacterCount: 1200, CharacterLimit: 500000})
+	})
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPing_InvalidKey(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(403, map[string]string{"message": "Wrong endpoint"})
+	})
+
+	err := client.Ping(context.Background())
+	if !IsAuthError(err) || !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 403 {
+		t.Errorf("expected wrapped *APIError with status 403, got %v", err)
+	}
+}
+
+func TestPing_NetworkError(t *testing.T) {
+	networkErr := errors.New("connection refused")
+	client := NewTestClient(nil)
+	client.httpClient.Transport = RoundTripErrFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, networkErr
+	})
+
+	err := client.Ping(context.Background())
+	if !errors.Is(err, networkErr) {
+		t.Fatalf("expected wrapped network error, got %v", err)
+	}
+	if IsAuthError(err) {
+		t.Errorf("expected no auth error, got %v", err)
+	}
+}
+
+func TestGetUsage_RetryOn503ThenSuccess(t *testing.T) {
+	attempt := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		attempt++
+		if attempt == 1 {
+			return MockResponse(503, map[string]string{"message": "service unavailable"})
+		}
+		return MockResponse(200, Usage{CharacterCount: 42, CharacterLimit: 500000})
+	})
+	client.retryPolicy = retryPolicy{MaxRetries: 3, MaxDelay: 10 * time.Millisecond}
+
+	usage, err := client.GetUsage()
+	if err != nil {
+		t.Fatalf("expected success after retry, got error %v", err)
+	}
+	if attempt != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempt)
+	}
+	if usage.CharacterCount != 42 {
+		t.Errorf("expected character count 42, got %d", usage.CharacterCount)
+	}
+}
+
+func TestUsage_NextResetTime(t *testing.T) {
+	start := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 14, 23, 59, 59, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		usage    Usage
+		expected time.Time
+		ok       bool
+	}{
+		{"end time", Usage{StartTime: &start, EndTime: &end}, end, true},
+		{"start time only", Usage{StartTime: &start}, time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC), true},
+		{"absent", Usage{}, time.Time{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.usage.NextResetTime()
+			if ok != tc.ok || !got.Equal(tc.expected) {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tc.expected, tc.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestUsage_NextResetTimeFromResponse(t *testing.T) {
+	var usage Usage
+	body := `{"character_count": 10, "character_limit": 100, "start_time": "2025-01-15T00:00:00Z", "end_time": "2025-02-15T00:00:00Z"}`
+	if err := json.Unmarshal([]byte(body), &usage); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reset, ok := usage.NextResetTime()
+	if !ok || !reset.Equal(time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected reset at 2025-02-15, got %v (%v)", reset, ok)
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	var requests int
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		if req.URL.Path != "/v2/usage" {
+			t.Errorf("expected request to /v2/usage, got %s", req.URL.Path)
+		}
+		return MockResponse(http.StatusOK, Usage{CharacterCount: 10, CharacterLimit: 100})
+	})
+
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("unexpected error on second call: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests after calling Warmup twice, got %d", requests)
+	}
+}
+
+func TestIsMockServer(t *testing.T) {
+	usageBody := `{"character_count":0,"character_limit":500000}`
+	// mockServer answers like deepl-mock: the first request of a session asking for a 429 gets one.
+	sessions := make(map[string]bool)
+	mockServer := func(req *http.Request) *http.Response {
+		session := req.Header.Get(mockSessionHeader)
+		header := http.Header{"X-Powered-By": {"Express"}}
+		if req.Header.Get(mockSession429Header) == "1" && !sessions[session] {
+			sessions[session] = true
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Body: io.NopCloser(strings.NewReader("")), Header: header}
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(usageBody)), Header: header}
+	}
+
+	testCases := []struct {
+		name     string
+		server   RoundTripFunc
+		expected bool
+	}{
+		{"mock server", mockServer, true},
+		{"DeepL API", func(req *http.Request) *http.Response {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(usageBody)),
+				Header: http.Header{"Content-Type": {"application/json"}}}
+		}, false},
+		{"Express proxy in front of the DeepL API", func(req *http.Request) *http.Response {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(usageBody)),
+				Header: http.Header{"X-Powered-By": {"Express"}}}
+		}, false},
+		{"rate-limited DeepL API", func(req *http.Request) *http.Response {
+			return MockResponse(http.StatusTooManyRequests, map[string]string{"message": "Too many requests"})
+		}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				requests++
+				if req.URL.Path != "/v2/usage" {
+					t.Errorf("expected request to /v2/usage, got %s", req.URL.Path)
+				}
+				if req.Header.Get("Authorization") != "DeepL-Auth-Key test-api-key" {
+					t.Errorf("unexpected Authorization header %q", req.Header.Get("Authorization"))
+				}
+				return tc.server(req)
+			})
+			client.retryPolicy = retryPolicy{MaxRetries: 2, MaxDelay: 10 * time.Millisecond}
+
+			mock, err := client.IsMockServer(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mock != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, mock)
+			}
+			if requests != 1 {
+				t.Errorf("expected a single request, got %d", requests)
+			}
+		})
+	}
+}
+
+func TestIsMockServer_Error(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return MockResponse(http.StatusForbidden, map[string]string{"message": "Forbidden"})
+	})
+
+	mock, err := client.IsMockServer(context.Background())
+	if mock || !hasStatusCode(err, http.StatusForbidden) {
+		t.Errorf("expected a 403 error, got %v and %v", mock, err)
+	}
+}
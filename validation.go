@@ -0,0 +1,118 @@
+package deepl
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxContextLength is the maximum number of characters of TranslateTextOptions.Context sent to DeepL.
+// The context is not billed, but overly long contexts are rejected by the API with a 400 error.
+const MaxContextLength = 4000
+
+// ValidationMode controls how the client reacts to suspicious request options before sending them.
+type ValidationMode int8
+
+const (
+	ValidationOff    ValidationMode = iota // No advisory warnings; invalid options are adjusted where possible (default)
+	ValidationWarn                         // Log advisory warnings; invalid options are adjusted where possible
+	ValidationStrict                       // Log advisory warnings and reject requests with invalid options
+)
+
+// WithValidationMode returns an Option that sets how strictly request options are checked before sending.
+func WithValidationMode(mode ValidationMode) Option {
+	return func(c *Client) {
+		c.validationMode = mode
+	}
+}
+
+// WithRequiredSourceLang returns an Option that disables source language auto-detection: every translation
+// must set SourceLang explicitly, otherwise it fails locally with ErrSourceLangRequired before any request is sent.
+// This is useful where the source language must be asserted, e.g. for compliance reasons.
+// DetectLanguage is exempt, as detecting the source language is its purpose.
+func WithRequiredSourceLang() Option {
+	return func(c *Client) {
+		c.requireSourceLang = true
+	}
+}
+
+// WithTestMode returns an Option for testing against the DeepL mock server or a similar stand-in.
+// It disables the client-side guards on translation options, i.e. WithRequiredSourceLang, the
+// context length check, and the HTML warnings, so that requests reach the server exactly as built
+// and the server's own behaviour can be tested. It takes precedence over WithValidationMode and
+// WithRequiredSourceLang regardless of the option order. Do not use it in production.
+func WithTestMode() Option {
+	return func(c *Client) {
+		c.testMode = true
+	}
+}
+
+// htmlTagPattern matches opening, closing, and self-closing tags of common HTML elements.
+// Restricting the match to known element names avoids flagging generics such as "List<String>" in code snippets.
+var htmlTagPattern = regexp.MustCompile(`(?i)</?(html|head|body|title|meta|link|script|style|div|span|p|a|br|hr|b|i|u|em|strong|small|sub|sup|code|pre|blockquote|ul|ol|li|dl|dt|dd|table|thead|tbody|tr|td|th|h[1-6]|img|section|article|header|footer|nav|main|aside|form|input|button|label|select|option|textarea)(\s[^<>]*)?/?>`)
+
+// htmlEntityPattern matches named and numeric HTML character references such as "&nbsp;" or "&#39;".
+var htmlEntityPattern = regexp.MustCompile(`&([a-zA-Z]{2,8}|#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6});`)
+
+// LooksLikeHTML reports whether text appears to contain HTML markup or HTML character references.
+// It is a best-effort heuristic meant for advisory warnings only and may miss unusual markup.
+func LooksLikeHTML(text string) bool {
+	if !strings.ContainsAny(text, "<&") {
+		return false
+	}
+	return htmlTagPattern.MatchString(text) || htmlEntityPattern.MatchString(text)
+}
+
+// warnf logs an advisory warning through the client's logger, falling back to the standard logger.
+func (c *Client) warnf(format string, args ...any) {
+	if c.logf != nil {
+		c.logf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// checkTranslateTextOptions runs the checks for a translation request according to the validation mode
+// and returns the options to send. A missing source language is rejected if auto-detection is disabled,
+// unless detect is set for a request that only detects the source language.
+// A context longer than MaxContextLength is rejected in strict mode and truncated with a warning otherwise.
+func (c *Client) checkTranslateTextOptions(opts TranslateTextOptions, detect bool) (TranslateTextOptions, error) {
+	if c.testMode {
+		return opts, nil
+	}
+
+	if c.requireSourceLang && !detect && strings.TrimSpace(opts.SourceLang) == "" {
+		return opts, ErrSourceLangRequired
+	}
+
+	if n := utf8.RuneCountInString(opts.Context); n > MaxContextLength {
+		if c.validationMode == ValidationStrict {
+			return opts, fmt.Errorf("context is %d characters long, exceeding the maximum of %d", n, MaxContextLength)
+		}
+		c.warnf("deepl: context is %d characters long, truncating it to %d", n, MaxContextLength)
+		opts.Context = truncateRunes(opts.Context, MaxContextLength)
+	}
+
+	if c.validationMode != ValidationOff && opts.TagHandling == "" {
+		for i, text := range opts.Text {
+			if LooksLikeHTML(text) {
+				c.warnf("deepl: text at index %d looks like HTML; consider setting TagHandling to \"html\"", i)
+				break
+			}
+		}
+	}
+	return opts, nil
+}
+
+// truncateRunes returns the first n runes of s.
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
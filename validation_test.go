@@ -0,0 +1,202 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLooksLikeHTML(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected bool
+	}{
+		{"<p>Hello world</p>", true},
+		{"Click <a href=\"/home\">here</a>", true},
+		{"Line one<br/>Line two", true},
+		{"Fish &amp; Chips", true},
+		{"It&#39;s fine", true},
+		{"Hello world", false},
+		{"if a < b && c > d { return }", false},
+		{"List<String> items = new ArrayList<>();", false},
+		{"Tom & Jerry", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			if got := LooksLikeHTML(tc.text); got != tc.expected {
+				t.Errorf("LooksLikeHTML(%q) = %v, expected %v", tc.text, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTranslateTextWithOptions_WarnsOnHTML(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        ValidationMode
+		tagHandling string
+		expectWarn  bool
+	}{
+		{"off", ValidationOff, "", false},
+		{"warn", ValidationWarn, "", true},
+		{"strict", ValidationStrict, "", true},
+		{"warn with tag handling", ValidationWarn, "html", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs []string
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				return MockResponse(200, TranslationsResponse{
+					Translations: []*Translation{{Text: "<p>Hallo</p>"}},
+				})
+			})
+			client.validationMode = tc.mode
+			client.logf = func(format string, args ...any) {
+				logs = append(logs, fmt.Sprintf(format, args...))
+			}
+
+			_, err := client.TranslateTextWithOptions(context.Background(), TranslateTextOptions{
+				Text:        []string{"<p>Hello</p>"},
+				TargetLang:  "DE",
+				TagHandling: tc.tagHandling,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			warned := len(logs) == 1 && strings.Contains(logs[0], "TagHandling")
+			if warned != tc.expectWarn {
+				t.Errorf("expected warning %v, got logs %q", tc.expectWarn, logs)
+			}
+		})
+	}
+}
+
+func TestTranslateTextWithOptions_ContextLength(t *testing.T) {
+	longContext := strings.Repeat("ä", MaxContextLength+10)
+
+	testCases := []struct {
+		name            string
+		context         string
+		mode            ValidationMode
+		expectError     bool
+		expectedContext string
+		expectWarn      bool
+	}{
+		{"within limit", "A greeting in an e-mail.", ValidationStrict, false, "A greeting in an e-mail.", false},
+		{"over limit in strict mode", longContext, ValidationStrict, true, "", false},
+		{"over limit in warn mode", longContext, ValidationWarn, false, strings.Repeat("ä", MaxContextLength), true},
+		{"over limit with validation off", longContext, ValidationOff, false, strings.Repeat("ä", MaxContextLength), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs []string
+			var sentContext string
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				body, _ := io.ReadAll(req.Body)
+				var requestData TranslateTextOptions
+				_ = json.Unmarshal(body, &requestData)
+				sentContext = requestData.Context
+				return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+			})
+			client.validationMode = tc.mode
+			client.logf = func(format string, args ...any) {
+				logs = append(logs, fmt.Sprintf(format, args...))
+			}
+
+			_, err := client.TranslateTextWithOptions(context.Background(), TranslateTextOptions{
+				Text:       []string{"Hello"},
+				TargetLang: "DE",
+				Context:    tc.context,
+			})
+			if tc.expectError {
+				if err == nil || !strings.Contains(err.Error(), "context is") {
+					t.Errorf("expected context length error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sentContext != tc.expectedContext {
+				t.Errorf("expected context of %d characters, got %d", len([]rune(tc.expectedContext)), len([]rune(sentContext)))
+			}
+			if warned := len(logs) > 0; warned != tc.expectWarn {
+				t.Errorf("expected warning %v, got logs %q", tc.expectWarn, logs)
+			}
+		})
+	}
+}
+
+func TestWithRequiredSourceLang(t *testing.T) {
+	requests := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+	})
+	WithRequiredSourceLang()(client)
+
+	_, err := client.TranslateText("Hello", "DE")
+	if !errors.Is(err, ErrSourceLangRequired) {
+		t.Errorf("expected ErrSourceLangRequired, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no request to be sent, got %d", requests)
+	}
+
+	_, err = client.TranslateTextWithOptions(context.Background(), TranslateTextOptions{
+		Text:       []string{"Hello"},
+		SourceLang: "EN",
+		TargetLang: "DE",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error with explicit source language: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected one request to be sent, got %d", requests)
+	}
+}
+
+func TestWithTestMode_RelaxesValidation(t *testing.T) {
+	longContext := strings.Repeat("a", MaxContextLength+1)
+
+	var sentContext string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		var opts TranslateTextOptions
+		_ = json.Unmarshal(body, &opts)
+		sentContext = opts.Context
+		return MockResponse(200, TranslationsResponse{Translations: []*Translation{{Text: "Hallo"}}})
+	})
+	WithRequiredSourceLang()(client)
+	WithValidationMode(ValidationStrict)(client)
+	WithTestMode()(client)
+
+	var warnings []string
+	client.logf = func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	_, err := client.TranslateTextWithOptions(context.Background(), TranslateTextOptions{
+		Text:       []string{"<p>Hello</p>"},
+		TargetLang: "DE",
+		Context:    longContext,
+	})
+	if err != nil {
+		t.Fatalf("expected no client-side error in test mode, got %v", err)
+	}
+	if sentContext != longContext {
+		t.Errorf("expected context to be sent unchanged, got %d characters", len(sentContext))
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings in test mode, got %q", warnings)
+	}
+}